@@ -0,0 +1,77 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// intervalFunc gets the next interval of the task from its statistics
+type intervalFunc func(stats TaskStats) time.Duration
+
+// SetAdaptiveInterval sets the fn of the frequently task, using the task name or the task ID, that is consulted
+// after each run to get its next interval from the task's statistics, e.g to poll less often as the errors
+// increase. The interval is rounded to whole seconds with a minimum of 1 second, a zero or negative interval
+// keeps the current one. Use a nil fn to stop the adaptation.
+func (t *TaskScheduler) SetAdaptiveInterval(taskName string, fn func(stats TaskStats) time.Duration) error {
+	if t.isClosed() {
+		return ErrSchedulerClosed
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		return fmt.Errorf("%s: %w", taskName, ErrTaskNotFound)
+	}
+	if taskData[0].RunType != _frequently || taskData[0].nextFunc != nil {
+		return fmt.Errorf("%s: %w", taskName, errors.New("adaptive interval is for the frequently option only"))
+	}
+	taskData[0].adaptive = fn
+	return nil
+}
+
+// adaptInterval sets the next interval of the task from its adaptive fn after a run
+func (t *TaskScheduler) adaptInterval(s *Tasks) {
+	t.mu.RLock()
+	cur, ok := t.TaskList[s.Name]
+	if !ok || len(cur) == 0 {
+		t.mu.RUnlock()
+		return
+	}
+	stats := cur[0].stats
+	t.mu.RUnlock()
+
+	d := s.adaptive(stats).Round(time.Second)
+	if d <= 0 {
+		return
+	}
+	if d < _minInterval {
+		d = _minInterval
+	}
+
+	t.mu.Lock()
+	cur, ok = t.TaskList[s.Name]
+	if !ok || len(cur) == 0 || cur[0].interval() == d {
+		t.mu.Unlock()
+		return
+	}
+	cur[0].setInterval(d)
+	next := cur[0].lastRunTime.Add(d)
+	if now := time.Now(); next.Before(now) {
+		next = now
+	}
+	cur[0].nextRunTime = next
+	t.emit(ChangeRescheduled, &cur[0])
+	logNextSched := t.allowLog(&cur[0])
+	t.mu.Unlock()
+	t.notify()
+
+	if !logNextSched {
+		return
+	}
+	msg := fmt.Sprintf("%s adapts its interval to %v", s.Name, d)
+	logger().Infow(msg, s.logKV()...)
+	color.Cyan(msg)
+}
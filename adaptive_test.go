@@ -0,0 +1,93 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLookupByID(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("poll").Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := ts.Info("poll")
+	id := info.ID
+
+	replaced := false
+	if err := ts.SetExecFunc(id, func() { replaced = true }); err != nil {
+		t.Fatalf("SetExecFunc by ID: %v", err)
+	}
+	cur, _ := ts.Get("poll")
+	cur[0].ExecuteFunc()
+	if !replaced {
+		t.Error("function is not replaced by ID")
+	}
+
+	if err := ts.SetAdaptiveInterval(id, func(stats TaskStats) time.Duration { return 2 * time.Minute }); err != nil {
+		t.Fatalf("SetAdaptiveInterval by ID: %v", err)
+	}
+	cur, _ = ts.Get("poll")
+	ts.adaptInterval(&cur[0])
+	if got, _ := ts.Info("poll"); got.Interval != 2*time.Minute {
+		t.Errorf("interval %v, want 2m from the adaptive func", got.Interval)
+	}
+
+	def, err := ts.Export(id)
+	if err != nil {
+		t.Fatalf("Export by ID: %v", err)
+	}
+	if def.Name != "poll" || def.ID != id {
+		t.Errorf("exported %s (%s), want poll (%s)", def.Name, def.ID, id)
+	}
+}
+
+func TestAdaptiveIntervalWidens(t *testing.T) {
+	ts := newTestScheduler()
+	fail := false
+	if _, err := ts.addTask(newTestTask("poll").Frequently().Minutes(1).ExecFuncErr(func() error {
+		if fail {
+			return errors.New("upstream unavailable")
+		}
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+	// One minute for every error percent above zero, e.g 50% of the runs failing gives 51 minutes
+	if err := ts.SetAdaptiveInterval("poll", func(stats TaskStats) time.Duration {
+		return time.Duration(1+stats.Errors*100/stats.Runs) * time.Minute
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var last time.Duration
+	for i, f := range []bool{false, true, true, true} {
+		fail = f
+		makeDue(t, ts, "poll")
+		ts.RunPending()
+		info, _ := ts.Info("poll")
+		if i > 0 && info.Interval <= last {
+			t.Errorf("run %d: interval %v after %d errors, want wider than %v", i+1, info.Interval, info.Stats.Errors, last)
+		}
+		last = info.Interval
+	}
+	if last != 76*time.Minute {
+		t.Errorf("interval %v with 3 of 4 runs failing, want 1h16m", last)
+	}
+
+	if err := ts.SetAdaptiveInterval("poll", nil); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "poll")
+	ts.RunPending()
+	if info, _ := ts.Info("poll"); info.Interval != last {
+		t.Errorf("interval %v without the adaptive func, want the unchanged %v", info.Interval, last)
+	}
+
+	if _, err := ts.addTask(newTestTask("daily").Daily().At("10:00").ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.SetAdaptiveInterval("daily", func(TaskStats) time.Duration { return time.Hour }); err == nil {
+		t.Error("adaptive interval is set on a daily task")
+	}
+}
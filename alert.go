@@ -0,0 +1,38 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// alertFunc gets the name of the task and how late its run is
+type alertFunc func(name string, overdue time.Duration)
+
+// AlertIfMissed method calls the alert func when a run of the task starts later than its schedule by more
+// than the duration, e.g to monitor the timeliness of the task. The alert func is called from the run itself.
+func (s *Tasks) AlertIfMissed(by time.Duration, alert func(name string, overdue time.Duration)) *Tasks {
+	if by < 0 || alert == nil {
+		s.setErr("AlertIfMissed", fmt.Errorf("invalid missed run alert, the duration %v must not be negative with an alert func", by))
+		return s
+	}
+	s.missedBy = by
+	s.missedAlert = alert
+	return s
+}
+
+// checkMissed calls the alert func if the run that starts at the given time is late by more than the threshold
+func (s *Tasks) checkMissed(start time.Time) {
+	if s.missedAlert == nil || s.nextRunTime.IsZero() {
+		return
+	}
+	overdue := start.Sub(s.nextRunTime)
+	if overdue <= s.missedBy {
+		return
+	}
+	msg := fmt.Sprintf("%s is late by %v, over its threshold of %v", s.Name, overdue, s.missedBy)
+	logger().Warnw(msg, s.logKV()...)
+	color.Yellow(msg)
+	s.missedAlert(s.Name, overdue)
+}
@@ -0,0 +1,41 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertIfMissed(t *testing.T) {
+	type alert struct {
+		name    string
+		overdue time.Duration
+	}
+	var alerts []alert
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("slo").Frequently().Minutes(1).AlertIfMissed(5*time.Second, func(name string, overdue time.Duration) {
+		alerts = append(alerts, alert{name, overdue})
+	}).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+
+	// Late but within the threshold
+	setNextRun(t, ts, "slo", time.Now().Add(-2*time.Second))
+	ts.RunPending()
+	if len(alerts) != 0 {
+		t.Fatalf("alerts %v for a run within the threshold, want none", alerts)
+	}
+
+	// Past the threshold
+	setNextRun(t, ts, "slo", time.Now().Add(-30*time.Second))
+	ts.RunPending()
+	if len(alerts) != 1 || alerts[0].name != "slo" || alerts[0].overdue < 30*time.Second || alerts[0].overdue > 31*time.Second {
+		t.Errorf("alerts %v, want one for slo late by about 30s", alerts)
+	}
+	if info, _ := ts.Info("slo"); info.Stats.Runs != 2 {
+		t.Errorf("%d runs, want the late run to still run", info.Stats.Runs)
+	}
+
+	if _, err := ts.addTask(newTestTask("no-func").Frequently().Minutes(1).AlertIfMissed(time.Second, nil).ExecFunc(func() {})); !hasProblem(err, "AlertIfMissed") {
+		t.Errorf("nil alert func error %v, want an AlertIfMissed problem", err)
+	}
+}
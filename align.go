@@ -0,0 +1,44 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// AlignTo reschedules the frequently tasks to run on 'epoch + k*interval', so the task schedulers of
+// several instances using the same epoch run in lockstep, use a different epoch to offset them.
+// Tasks that use the 'NextFunc' method are not changed.
+func (t *TaskScheduler) AlignTo(epoch time.Time) {
+	now := time.Now()
+	aligned := 0
+	t.mu.Lock()
+	for _, e := range t.TaskList {
+		for i := range e {
+			s := &e[i]
+			if s.RunType != _frequently || s.nextFunc != nil || s.interval() <= 0 {
+				continue
+			}
+			s.nextRunTime = alignedRun(epoch, s.interval(), now)
+			t.emit(ChangeRescheduled, s)
+			aligned++
+		}
+	}
+	t.mu.Unlock()
+	t.notify()
+
+	alignedTo, _ := formatDT(epoch, logDateTimeFormat)
+	msg := fmt.Sprintf("%d frequently tasks are aligned to: %s", aligned, alignedTo)
+	logger().Infow(msg, "log_time", now.Format(logDateTimeFormat))
+	color.Cyan(msg)
+}
+
+// alignedRun returns the first 'epoch + k*interval' after the 'now' time, k can be negative
+func alignedRun(epoch time.Time, interval time.Duration, now time.Time) time.Time {
+	offset := now.Sub(epoch) % interval
+	if offset < 0 {
+		offset += interval
+	}
+	return now.Add(interval - offset)
+}
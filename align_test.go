@@ -0,0 +1,47 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlignTo(t *testing.T) {
+	for _, epoch := range []time.Time{
+		time.Date(2020, 1, 1, 0, 0, 7, 0, time.UTC),
+		time.Now().Add(100 * time.Hour).Truncate(time.Second).Add(3 * time.Second), // k is negative
+	} {
+		ts, other := newTestScheduler(), newTestScheduler()
+		intervals := map[string]time.Duration{"poll": 30 * time.Second, "sync": 5 * time.Minute, "report": 2 * time.Hour}
+		for name, d := range intervals {
+			for _, sched := range []*TaskScheduler{ts, other} {
+				if _, err := sched.addTask(newTestTask(name).Frequently().Seconds(int(d / time.Second)).ExecFunc(func() {})); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if _, err := ts.addTask(newTestTask("daily").Daily().At("10:00").ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+		daily, _ := ts.Info("daily")
+
+		ts.AlignTo(epoch)
+		other.AlignTo(epoch)
+		now := time.Now()
+		for name, d := range intervals {
+			info, _ := ts.Info(name)
+			if off := info.NextRun.Sub(epoch) % d; off != 0 {
+				t.Errorf("%s: next run %v is off the epoch %v by %v", name, info.NextRun, epoch, off)
+			}
+			if !info.NextRun.After(now.Add(-time.Second)) || info.NextRun.Sub(now) > d {
+				t.Errorf("%s: next run %v, want the first aligned run after now", name, info.NextRun)
+			}
+			// The same epoch puts both instances in lockstep, on the wall clock as they're different processes
+			if o, _ := other.Info(name); !o.NextRun.Round(0).Equal(info.NextRun.Round(0)) {
+				t.Errorf("%s: instances run at %v and %v, want the same run", name, info.NextRun, o.NextRun)
+			}
+		}
+		if info, _ := ts.Info("daily"); !info.NextRun.Equal(daily.NextRun) {
+			t.Errorf("daily task is moved from %v to %v", daily.NextRun, info.NextRun)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package isked
+
+import (
+	"time"
+)
+
+// Backfill method sets the maximum number of missed runs to catch up on when the task is
+// added with a past 'StartingFrom' DateTime or imported with a past next run, e.g a daily
+// task that missed three days with 'Backfill(2)' runs twice right away then follows its schedule.
+func (s *Tasks) Backfill(max int) *Tasks {
+	if max < 0 {
+		max = 0
+	}
+	s.backfill = max
+	return s
+}
+
+// missedRuns counts the scheduled runs from the 'from' DateTime up to now, up to the backfill maximum
+func (s *Tasks) missedRuns(from, now time.Time) int {
+	missed := 0
+	for next := from; !next.IsZero() && !next.After(now) && missed < s.backfill; missed++ {
+		next = s.nextSchedule(next)
+	}
+	return missed
+}
+
+// prepareBackfill makes the task due right away if it has missed runs to catch up on, it returns false if there's none
+func (s *Tasks) prepareBackfill(from, now time.Time) bool {
+	if s.backfill == 0 || s.RunType == _onetime {
+		return false
+	}
+	missed := s.missedRuns(from, now)
+	if missed == 0 {
+		return false
+	}
+	s.pendingBackfill = missed
+	s.nextRunTime = now
+	return true
+}
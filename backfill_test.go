@@ -0,0 +1,36 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackfill(t *testing.T) {
+	for _, tt := range []struct {
+		backfill, want int
+	}{{2, 2}, {5, 3}, {0, 0}} {
+		ts := newTestScheduler()
+		runs := 0
+		// Missed the runs at 09:00 on each of the last three days
+		now := time.Now()
+		start := time.Date(now.Year(), now.Month(), now.Day()-3, 8, 0, 0, 0, time.Local)
+		if now.Hour() >= 9 {
+			start = start.AddDate(0, 0, 1) // Today's run is one of them
+		}
+		s := newTestTask("reconcile").Daily().At("09:00").StartingFrom(start).Backfill(tt.backfill).ExecFunc(func() { runs++ })
+		if _, err := ts.addTask(s); err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 5; i++ {
+			ts.RunPending()
+		}
+		if runs != tt.want {
+			t.Errorf("Backfill(%d): %d catch-up runs, want %d", tt.backfill, runs, tt.want)
+		}
+		info, _ := ts.Info("reconcile")
+		if at := info.NextRun.In(time.Local); !at.After(now) || at.Hour() != 9 || at.Minute() != 0 {
+			t.Errorf("Backfill(%d): next run %v, want back on the schedule at 09:00", tt.backfill, at)
+		}
+	}
+}
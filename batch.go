@@ -0,0 +1,104 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+)
+
+// AddBatchAt adds all the tasks at once with the same first run at the given DateTime so they fire together,
+// the next runs follow their own schedule, a DateTime in the past runs them right away. Either all the tasks
+// are added or none of them, the returned errors are in the same order as the tasks and nil if none of the
+// tasks has an error.
+func (t *TaskScheduler) AddBatchAt(at time.Time, tasks ...*Tasks) []error {
+	errs := make([]error, len(tasks))
+	if t.isClosed() {
+		for i := range errs {
+			errs[i] = ErrSchedulerClosed
+		}
+		return errs
+	}
+	if at.IsZero() {
+		for i := range errs {
+			errs[i] = errors.New("missing DateTime of the first run")
+		}
+		return errs
+	}
+	failed := false
+	seen := make(map[string]bool, len(tasks))
+	newTasks := make([]Tasks, len(tasks))
+	created := time.Now()
+	for i, s := range tasks {
+		switch {
+		case s == nil:
+			errs[i] = fmt.Errorf("missing task at index %d", i)
+		case len(s.Name) == 0:
+			errs[i] = fmt.Errorf("missing task name at index %d", i)
+		case seen[s.Name]:
+			errs[i] = fmt.Errorf("%s: %w", s.Name, ErrTaskExists)
+		default:
+			seen[s.Name] = true
+			if err := s.validate(); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", s.Name, err)
+				break
+			}
+			newTasks[i] = *s
+			if err := newTasks[i].setFirstRun(at, created); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", s.Name, err)
+			}
+		}
+		failed = failed || errs[i] != nil
+	}
+	if failed {
+		return errs
+	}
+
+	t.mu.Lock()
+	for i, s := range tasks {
+		if _, ok := t.TaskList[s.Name]; ok {
+			errs[i] = fmt.Errorf("%s: %w", s.Name, ErrTaskExists)
+			failed = true
+		}
+	}
+	if !failed {
+		names := make([]string, 0, len(tasks))
+		for _, s := range tasks {
+			names = append(names, s.Name)
+		}
+		if err := t.checkMaxTasks(names...); err != nil {
+			for i, s := range tasks {
+				errs[i] = fmt.Errorf("%s: %w", s.Name, err)
+			}
+			failed = true
+		}
+	}
+	if failed {
+		t.mu.Unlock()
+		return errs
+	}
+	for i := range newTasks {
+		if len(newTasks[i].id) == 0 {
+			newTasks[i].id = uuid.New().String()
+		}
+		newTasks[i].defaultLoc = t.defaultLoc
+		newTasks[i].weekStart = t.weekStart
+		newTasks[i].prepareGate()
+		t.TaskList[newTasks[i].Name] = []Tasks{newTasks[i]}
+	}
+	t.mu.Unlock()
+	t.notify()
+
+	for _, s := range newTasks {
+		t.emit(ChangeAdded, &s)
+		t.gate(s)
+
+		nextSched, _ := formatDT(s.nextRunTime, logDateTimeFormat)
+		msg := s.Name + " base start datetime at: " + nextSched
+		logger().Infow(msg, s.logKV()...)
+		color.Cyan(msg)
+	}
+	return nil
+}
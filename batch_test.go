@@ -0,0 +1,65 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddBatchAt(t *testing.T) {
+	ts := newTestScheduler()
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	errs := ts.AddBatchAt(at,
+		newTestTask("first").Frequently().Minutes(5).ExecFunc(func() {}),
+		newTestTask("second").Daily().At("03:00").ExecFunc(func() {}),
+	)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	for _, name := range []string{"first", "second"} {
+		if info, _ := ts.Info(name); !info.NextRun.Equal(at) {
+			t.Errorf("%s next run %v, want %v", name, info.NextRun, at)
+		}
+	}
+}
+
+func TestAddBatchAtAllOrNothing(t *testing.T) {
+	ts := newTestScheduler()
+	errs := ts.AddBatchAt(time.Now().Add(time.Hour),
+		newTestTask("valid").Daily().At("03:00").ExecFunc(func() {}),
+		newTestTask("invalid").Daily().At("25:00").ExecFunc(func() {}),
+	)
+	if len(errs) != 2 || errs[0] != nil || errs[1] == nil {
+		t.Fatalf("errs = %v, want an error for the second task only", errs)
+	}
+	if n := ts.taskCount(); n != 0 {
+		t.Fatalf("%d tasks are added, want none", n)
+	}
+}
+
+func TestAddBatchAtRejects(t *testing.T) {
+	ts := newTestScheduler()
+	if errs := ts.AddBatchAt(time.Time{}, newTestTask("zero").Daily().At("03:00").ExecFunc(func() {})); len(errs) != 1 || errs[0] == nil {
+		t.Errorf("zero DateTime errs = %v, want an error", errs)
+	}
+
+	now := time.Now()
+	ended := newTestTask("ranged").Daily().At("03:00").Between(now, now.Add(time.Hour)).ExecFunc(func() {})
+	if errs := ts.AddBatchAt(now.Add(2*time.Hour), ended); len(errs) != 1 || errs[0] == nil {
+		t.Errorf("first run after the date range errs = %v, want an error", errs)
+	}
+	if n := ts.taskCount(); n != 0 {
+		t.Fatalf("%d tasks are added, want none", n)
+	}
+}
+
+func TestAddBatchAtImmediately(t *testing.T) {
+	ts := newTestScheduler()
+	at := time.Now().Add(time.Hour)
+	if errs := ts.AddBatchAt(at, newTestTask("now").Daily().At("03:00").Immediately().ExecFunc(func() {})); errs != nil {
+		t.Fatal(errs)
+	}
+	l, _ := ts.Get("now")
+	if time.Until(l[0].nextRunTime) > time.Second || !l[0].firstRunAt.Equal(at) {
+		t.Errorf("next run %v, then %v, want now then %v", l[0].nextRunTime, l[0].firstRunAt, at)
+	}
+}
@@ -0,0 +1,99 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Blackout method sets a daily window using the 24-hour clock, e.g "01:00" to "03:30:00",
+// during which the task won't run. A run that is due inside the window is deferred to
+// the end of the window unless 'SkipBlackout' is used. The window can cross midnight, e.g "23:00" to "02:00".
+func (s *Tasks) Blackout(start, end string) *Tasks {
+	startHour, startMinute, startSecond, err := parseClockTime(start)
+	if err != nil {
+		s.setErr("Blackout", fmt.Errorf("invalid blackout window start: %w", err))
+		return s
+	}
+	endHour, endMinute, endSecond, err := parseClockTime(end)
+	if err != nil {
+		s.setErr("Blackout", fmt.Errorf("invalid blackout window end: %w", err))
+		return s
+	}
+	s.blackoutStart = startHour*3600 + startMinute*60 + startSecond
+	s.blackoutEnd = endHour*3600 + endMinute*60 + endSecond
+	if s.blackoutStart == s.blackoutEnd {
+		s.setErr("Blackout", errors.New("invalid blackout window, the start and end time must not be the same"))
+		return s
+	}
+	s.hasBlackout = true
+	return s
+}
+
+// SkipBlackout method skips the run that is due inside the blackout window instead of
+// deferring it, the task runs again on its next schedule after the window. The run of the
+// onetime option is never skipped, it's deferred to the end of the window.
+func (s *Tasks) SkipBlackout() *Tasks {
+	s.skipBlackout = true
+	return s
+}
+
+// blackoutEndsAt returns the end of the blackout window if the 'now' time is inside it
+func (s *Tasks) blackoutEndsAt(now time.Time) (time.Time, bool) {
+	if !s.hasBlackout {
+		return time.Time{}, false
+	}
+	now = now.In(s.location())
+	secs := now.Hour()*3600 + now.Minute()*60 + now.Second()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch {
+	case s.blackoutStart < s.blackoutEnd:
+		if secs >= s.blackoutStart && secs < s.blackoutEnd {
+			return midnight.Add(time.Duration(s.blackoutEnd) * time.Second), true
+		}
+	case secs >= s.blackoutStart:
+		// Crosses midnight, the window ends tomorrow
+		return midnight.AddDate(0, 0, 1).Add(time.Duration(s.blackoutEnd) * time.Second), true
+	case secs < s.blackoutEnd:
+		return midnight.Add(time.Duration(s.blackoutEnd) * time.Second), true
+	}
+	return time.Time{}, false
+}
+
+// deferBlackout moves the due run of the task out of its blackout window, it returns false if
+// the task is not inside its blackout window.
+func (t *TaskScheduler) deferBlackout(s *Tasks, now time.Time) bool {
+	windowEnd, ok := s.blackoutEndsAt(now)
+	if !ok {
+		return false
+	}
+
+	nextSchedToRun := windowEnd
+	if s.skipBlackout && s.RunType != _onetime {
+		nextSchedToRun = s.nextSchedule(windowEnd)
+	}
+	if nextSchedToRun.IsZero() {
+		nextSchedToRun = windowEnd // No next schedule to skip to, e.g the 'NextAt' func has none
+	}
+
+	t.mu.Lock()
+	logNextSched := false
+	if cur, ok := t.TaskList[s.Name]; ok && len(cur) > 0 {
+		logNextSched = t.allowLog(&cur[0])
+		cur[0].nextRunTime = nextSchedToRun
+		t.emit(ChangeRescheduled, &cur[0])
+	}
+	t.mu.Unlock()
+
+	if !logNextSched {
+		return true
+	}
+	nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
+	msg := s.Name + " is in its blackout window, next schedule to run on: " + nextSched
+	logger().Infow(msg, s.logKV()...)
+	color.Magenta(msg)
+	return true
+}
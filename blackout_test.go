@@ -0,0 +1,42 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlackoutDefers(t *testing.T) {
+	ts := newTestScheduler()
+	at := time.Date(2030, time.January, 1, 2, 0, 0, 0, time.Local)
+	windowEnd := time.Date(2030, time.January, 1, 3, 0, 0, 0, time.Local)
+	tasks := []*Tasks{
+		newTestTask("deferred").Frequently().Minutes(5).Blackout("01:00", "03:00").ExecFunc(func() {}),
+		newTestTask("skipped").Frequently().Minutes(7).Blackout("01:00", "03:00").SkipBlackout().ExecFunc(func() {}),
+		newTestTask("onetime").OneTime(at.Unix()).Blackout("01:00", "03:00").SkipBlackout().ExecFunc(func() {}),
+		newTestTask("next-at").NextAt(func() time.Time { return time.Time{} }).Blackout("01:00", "03:00").SkipBlackout().ExecFunc(func() {}),
+	}
+	want := map[string]time.Time{
+		"deferred": windowEnd,
+		"skipped":  windowEnd.Add(7 * time.Minute),
+		"onetime":  windowEnd,
+		"next-at":  windowEnd,
+	}
+	for _, s := range tasks {
+		s.nextRunTime = at
+		ts.TaskList[s.Name] = []Tasks{*s}
+	}
+
+	for _, s := range tasks {
+		if !ts.deferBlackout(s, at) {
+			t.Fatalf("%s: not in its blackout window at %v", s.Name, at)
+		}
+		cur, _ := ts.Get(s.Name)
+		if got := cur[0].nextRunTime; !got.Equal(want[s.Name]) {
+			t.Errorf("%s: next run %v, want %v", s.Name, got, want[s.Name])
+		}
+	}
+
+	if ts.deferBlackout(tasks[0], windowEnd) {
+		t.Error("deferred at the end of the window")
+	}
+}
@@ -0,0 +1,173 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+)
+
+// TaskBuilder builds a task like the chained methods but also reports the invalid values that they default,
+// e.g a negative 'Limit'. Use 'Build' to get all the errors at once, e.g for the tasks from a config file:
+//
+//	s, err := isked.NewTaskBuilder("Report").Daily().At(cfg.At).Limit(cfg.Limit).ExecFunc(myFunc).Build()
+//	if err != nil {
+//		return err // lists every invalid setting
+//	}
+//	s.Add()
+type TaskBuilder struct {
+	s *Tasks
+}
+
+// NewTaskBuilder creates the task builder, a suffix is added if the task name is already in use.
+func NewTaskBuilder(taskName string) *TaskBuilder {
+	return &TaskBuilder{
+		s: &Tasks{
+			Name:      TS.uniqueName(taskName),
+			monthName: time.Now().Local().Month(),
+			created:   time.Now(),
+		},
+	}
+}
+
+// step applies the setting to the task, its problems are kept by the task
+func (b *TaskBuilder) step(set func(s *Tasks)) *TaskBuilder {
+	set(b.s)
+	return b
+}
+
+// Frequently is the same as the 'Frequently' method of the task
+func (b *TaskBuilder) Frequently() *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Frequently() })
+}
+
+// Seconds is the same as the 'Seconds' method of the task
+func (b *TaskBuilder) Seconds(interval int) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Seconds(interval) })
+}
+
+// Minutes is the same as the 'Minutes' method of the task
+func (b *TaskBuilder) Minutes(interval int) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Minutes(interval) })
+}
+
+// Hours is the same as the 'Hours' method of the task
+func (b *TaskBuilder) Hours(interval int) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Hours(interval) })
+}
+
+// Daily is the same as the 'Daily' method of the task
+func (b *TaskBuilder) Daily() *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Daily() })
+}
+
+// Weekly is the same as the 'Weekly' method of the task
+func (b *TaskBuilder) Weekly() *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Weekly() })
+}
+
+// Monthly is the same as the 'Monthly' method of the task
+func (b *TaskBuilder) Monthly() *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Monthly() })
+}
+
+// OneTime is the same as the 'OneTime' method of the task but a past DateTime is an error
+func (b *TaskBuilder) OneTime(dt time.Time) *TaskBuilder {
+	if dt.Before(time.Now()) {
+		b.s.setErr("OneTime", fmt.Errorf("onetime DateTime %v is in the past", dt))
+		return b
+	}
+	return b.step(func(s *Tasks) { s.OneTime(dt.Unix()) })
+}
+
+// On sets the day of the week of the weekly option
+func (b *TaskBuilder) On(day time.Weekday) *TaskBuilder {
+	if day < time.Sunday || day > time.Saturday {
+		b.s.setErr("On", fmt.Errorf("invalid weekday %d", day))
+		return b
+	}
+	return b.step(func(s *Tasks) { s.dayName = day })
+}
+
+// Every is the same as the 'Every' method of the task but a day outside 0 to 31 is an error
+func (b *TaskBuilder) Every(day int) *TaskBuilder {
+	if day < 0 || day > 31 {
+		b.s.setErr("Every", fmt.Errorf("invalid month day %d, use 1 to 31 or 0 for the last day", day))
+		return b
+	}
+	return b.step(func(s *Tasks) { s.Every(day) })
+}
+
+// At is the same as the 'At' method of the task
+func (b *TaskBuilder) At(rt string) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.At(rt) })
+}
+
+// In is the same as the 'In' method of the task
+func (b *TaskBuilder) In(loc *time.Location) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.In(loc) })
+}
+
+// Limit is the same as the 'Limit' method of the task but a negative number is an error
+func (b *TaskBuilder) Limit(runs int) *TaskBuilder {
+	if runs < 0 {
+		b.s.setErr("Limit", fmt.Errorf("invalid limit %d, use 0 for no limit", runs))
+		return b
+	}
+	return b.step(func(s *Tasks) { s.Limit(runs) })
+}
+
+// StartingFrom is the same as the 'StartingFrom' method of the task
+func (b *TaskBuilder) StartingFrom(dt time.Time) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.StartingFrom(dt) })
+}
+
+// Between is the same as the 'Between' method of the task
+func (b *TaskBuilder) Between(start, end time.Time) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Between(start, end) })
+}
+
+// Blackout is the same as the 'Blackout' method of the task
+func (b *TaskBuilder) Blackout(start, end string) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.Blackout(start, end) })
+}
+
+// ISO8601 is the same as the 'ISO8601' method of the task
+func (b *TaskBuilder) ISO8601(expr string) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.ISO8601(expr) })
+}
+
+// ExecFunc is the same as the 'ExecFunc' method of the task
+func (b *TaskBuilder) ExecFunc(fn FuncToExec) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.ExecFunc(fn) })
+}
+
+// ExecFuncErr is the same as the 'ExecFuncErr' method of the task
+func (b *TaskBuilder) ExecFuncErr(fn FuncToExecErr) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.ExecFuncErr(fn) })
+}
+
+// ExecFuncCtx is the same as the 'ExecFuncCtx' method of the task
+func (b *TaskBuilder) ExecFuncCtx(fn FuncToExecCtx) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.ExecFuncCtx(fn) })
+}
+
+// ExecFuncMeta is the same as the 'ExecFuncMeta' method of the task
+func (b *TaskBuilder) ExecFuncMeta(fn FuncToExecMeta) *TaskBuilder {
+	return b.step(func(s *Tasks) { s.ExecFuncMeta(fn) })
+}
+
+// Apply calls any other chained method of the task and keeps its error, e.g
+// '.Apply(func(s *isked.Tasks) *isked.Tasks { return s.Shards(4) })'
+func (b *TaskBuilder) Apply(fn func(s *Tasks) *Tasks) *TaskBuilder {
+	return b.step(func(s *Tasks) { fn(s) })
+}
+
+// Build checks the task and returns it ready to be added, or the 'ValidationError' with every problem found
+// like the 'Validate' method.
+func (b *TaskBuilder) Build() (*Tasks, error) {
+	s := *b.s
+	s.problems = append([]FieldError(nil), b.s.problems...)
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
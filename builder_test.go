@@ -0,0 +1,69 @@
+package isked
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildValidationError(t *testing.T) {
+	_, err := NewTaskBuilder("Broken").Monthly().Every(40).At("10:00").Limit(-1).Build()
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("error %v is not a ValidationError", err)
+	}
+	fields := make(map[string]bool)
+	for _, p := range invalid.Problems {
+		fields[p.Field] = true
+	}
+	for _, field := range []string{"Every", "Limit", "ExecFunc"} {
+		if !fields[field] {
+			t.Errorf("missing the %s problem in %v", field, err)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	b := NewTaskBuilder("Report").Daily().At("10:00").Limit(3)
+	if _, err := b.Build(); err == nil {
+		t.Fatal("built without a function to execute")
+	}
+	s, err := b.ExecFunc(func() {}).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.limit != 3 || s.RunType != _daily {
+		t.Errorf("built %s task with limit %d", s.RunType, s.limit)
+	}
+
+	ts := newTestScheduler()
+	if _, err := ts.AddTask(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildAccumulatesStepErrors(t *testing.T) {
+	_, err := NewTaskBuilder("Config").Weekly().On(time.Weekday(9)).At("bad").Limit(-2).
+		Apply(func(s *Tasks) *Tasks { return s.Shards(0) }).ExecFunc(func() {}).Build()
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("error %v is not a ValidationError", err)
+	}
+	for _, field := range []string{"On", "At", "Limit", "Shards"} {
+		if !hasProblem(err, field) {
+			t.Errorf("missing the %s problem in %v", field, err)
+		}
+	}
+	// The combined message lists every problem
+	for _, text := range []string{"invalid weekday 9", `invalid 'At' time "bad"`, "invalid limit -2", "invalid number of shards 0"} {
+		if !strings.Contains(err.Error(), text) {
+			t.Errorf("error %q doesn't mention %q", err, text)
+		}
+	}
+
+	_, err = NewTaskBuilder("Past").OneTime(time.Now().Add(-time.Hour)).ExecFunc(func() {}).Build()
+	if !hasProblem(err, "OneTime") {
+		t.Errorf("past DateTime error %v, want a OneTime problem", err)
+	}
+}
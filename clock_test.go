@@ -0,0 +1,60 @@
+package isked
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseClockTime(t *testing.T) {
+	tests := []struct {
+		in                   string
+		hour, minute, second int
+		ok                   bool
+	}{
+		{"15:04", 15, 4, 0, true},
+		{"00:00", 0, 0, 0, true},
+		{"23:59:59", 23, 59, 59, true},
+		{" 09:30 ", 9, 30, 0, true},
+		{"24:00", 0, 0, 0, false},
+		{"15:60", 0, 0, 0, false},
+		{"12:00:60", 0, 0, 0, false},
+		{"9:30", 0, 0, 0, false},
+		{"1:2:3:4", 0, 0, 0, false},
+		{":", 0, 0, 0, false},
+		{"12:", 0, 0, 0, false},
+		{"", 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		hour, minute, second, err := parseClockTime(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseClockTime(%q) error = %v, want ok %v", tt.in, err, tt.ok)
+			continue
+		}
+		if tt.ok && (hour != tt.hour || minute != tt.minute || second != tt.second) {
+			t.Errorf("parseClockTime(%q) = %d:%d:%d, want %d:%d:%d", tt.in, hour, minute, second, tt.hour, tt.minute, tt.second)
+		}
+	}
+}
+
+func FuzzParseClockTime(f *testing.F) {
+	for _, seed := range []string{"15:04", "23:59:59", ":", "12:", "1:2:3:4", "24:00", "15:60", ""} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, v string) {
+		hour, minute, second, err := parseClockTime(v)
+		if err != nil {
+			if hour != 0 || minute != 0 || second != 0 {
+				t.Fatalf("parseClockTime(%q) = %d:%d:%d with error %v", v, hour, minute, second, err)
+			}
+			return
+		}
+		if hour < 0 || hour > 23 || minute < 0 || minute > 59 || second < 0 || second > 59 {
+			t.Fatalf("parseClockTime(%q) = %d:%d:%d is out of range", v, hour, minute, second)
+		}
+		// A valid time formats back to the same clock
+		again, minuteAgain, secondAgain, err := parseClockTime(fmt.Sprintf("%02d:%02d:%02d", hour, minute, second))
+		if err != nil || again != hour || minuteAgain != minute || secondAgain != second {
+			t.Fatalf("parseClockTime(%q) = %d:%d:%d doesn't round trip: %v", v, hour, minute, second, err)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package isked
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// ErrSchedulerClosed is returned when the task scheduler is used after the 'Close' method
+var ErrSchedulerClosed = errors.New("task scheduler is closed")
+
+// Close stops the running loop, waits for the runs that are in progress and removes all the tasks including
+// the namespaces. The task scheduler can't be used after it's closed, its methods return 'ErrSchedulerClosed'.
+func (t *TaskScheduler) Close() error {
+	if !atomic.CompareAndSwapInt32(&t.closed, 0, 1) {
+		return ErrSchedulerClosed
+	}
+	if t.parent == nil {
+		t.stop(ErrSchedulerClosed) // Namespaces share the loop of their parent, it keeps running
+	}
+
+	all := t.withNamespaces()
+	waitRuns(all)
+	removed := removeAll(all)
+
+	msg := "task scheduler is closed"
+	logger().Infow(msg, "log_time", time.Now().Format(logDateTimeFormat), "removed", removed)
+	color.Yellow(msg)
+	return nil
+}
+
+// Shutdown stops the running loop like the 'Close' method and cancels the contexts of the 'ExecFuncCtx' runs
+// that are in progress so they can wind down, e.g to save a checkpoint. It waits for the runs until the context
+// is done and returns its error if some runs are still in progress, the task scheduler is closed either way.
+// The runs of a namespace share the context of its parent, they're only cancelled when the parent is shut down.
+func (t *TaskScheduler) Shutdown(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&t.closed, 0, 1) {
+		return ErrSchedulerClosed
+	}
+	if t.parent == nil {
+		t.stop(ErrSchedulerClosed)
+	}
+	t.cancelRunsContext()
+
+	all := t.withNamespaces()
+	drained := make(chan struct{})
+	go func() {
+		waitRuns(all)
+		close(drained)
+	}()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	removed := removeAll(all)
+
+	msg := "task scheduler is shut down"
+	if err != nil {
+		msg += " with runs still in progress: " + err.Error()
+	}
+	logger().Infow(msg, "log_time", time.Now().Format(logDateTimeFormat), "removed", removed)
+	color.Yellow(msg)
+	return err
+}
+
+// beginRun counts a new run in the runs in progress, it returns false if the task scheduler is closed
+func (t *TaskScheduler) beginRun() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.isClosed() {
+		return false
+	}
+	t.inFlight.Add(1)
+	return true
+}
+
+// waitRuns waits for the runs in progress of the closed task schedulers, each lock is taken first so a run
+// that is being counted by 'beginRun' is done before waiting, and no run is counted after it
+func waitRuns(all []*TaskScheduler) {
+	for _, ts := range all {
+		ts.mu.Lock() // Only waits for the 'beginRun' in progress
+		ts.mu.Unlock()
+		ts.inFlight.Wait()
+	}
+}
+
+// removeAll removes all the tasks of the task schedulers and closes their watch channels, it returns the
+// number of tasks removed
+func removeAll(all []*TaskScheduler) int {
+	removed := 0
+	for _, ts := range all {
+		ts.mu.Lock()
+		removed += len(ts.TaskList)
+		ungateAll(ts.TaskList)
+		ts.TaskList = make(map[string][]Tasks)
+		ts.mu.Unlock()
+		ts.closeWatchers()
+	}
+	return removed
+}
+
+// runsContext returns the parent of the contexts of the runs, the namespaces use the one of their parent
+func (t *TaskScheduler) runsContext() context.Context {
+	if t.parent != nil {
+		return t.parent.runsContext()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.runsCtx == nil {
+		t.runsCtx, t.cancelRuns = context.WithCancel(context.Background())
+	}
+	return t.runsCtx
+}
+
+// cancelRunsContext cancels the contexts of the runs in progress, the next runs get a new one
+func (t *TaskScheduler) cancelRunsContext() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cancelRuns != nil {
+		t.cancelRuns()
+	}
+	t.runsCtx, t.cancelRuns = nil, nil
+}
+
+// isClosed checks if the task scheduler or the parent of the namespace is closed
+func (t *TaskScheduler) isClosed() bool {
+	if atomic.LoadInt32(&t.closed) == 1 {
+		return true
+	}
+	return t.parent != nil && t.parent.isClosed()
+}
@@ -0,0 +1,198 @@
+package isked
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCloseWaitsForRuns(t *testing.T) {
+	ts := newTestScheduler()
+	var done int32
+	s := newTestTask("slow").Frequently().Seconds(1).Immediately().ExecFunc(func() {
+		time.Sleep(200 * time.Millisecond)
+		atomic.StoreInt32(&done, 1)
+	})
+	if _, err := ts.addTask(s); err != nil {
+		t.Fatal(err)
+	}
+	ts.runPending(time.Now())
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&done) == 0 {
+		t.Fatal("Close returned before the run in progress is done")
+	}
+	if err := ts.Close(); !errors.Is(err, ErrSchedulerClosed) {
+		t.Fatalf("second Close = %v, want ErrSchedulerClosed", err)
+	}
+}
+
+// TestCloseDuringDispatch closes while a dispatch pass is starting the runs, no run may start after Close returns
+func TestCloseDuringDispatch(t *testing.T) {
+	ts := newTestScheduler()
+	var started, dispatching int32
+	var passing sync.Once
+	inPass := make(chan struct{})
+	for i := 0; i < 20; i++ {
+		// The next schedule is computed before each run starts, it slows down the dispatch pass
+		s := newTestTask("due" + strconv.Itoa(i)).NextFunc(func() time.Duration {
+			if atomic.LoadInt32(&dispatching) == 1 {
+				passing.Do(func() { close(inPass) })
+				time.Sleep(5 * time.Millisecond)
+			}
+			return time.Hour
+		}).ExecFunc(func() {
+			atomic.AddInt32(&started, 1)
+		})
+		if _, err := ts.addTask(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	atomic.StoreInt32(&dispatching, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ts.runPending(time.Now().Add(2 * time.Hour))
+	}()
+	<-inPass
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+	afterClose := atomic.LoadInt32(&started)
+	wg.Wait()
+	time.Sleep(10 * time.Millisecond)
+	if n := atomic.LoadInt32(&started); n != afterClose {
+		t.Fatalf("%d runs started after Close returned", n-afterClose)
+	}
+}
+
+func TestMethodsAfterClose(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("closed").Daily().At("09:00").ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ts.addTask(newTestTask("late").Daily().At("09:00").ExecFunc(func() {})); !errors.Is(err, ErrSchedulerClosed) {
+		t.Errorf("addTask = %v, want ErrSchedulerClosed", err)
+	}
+	if err := ts.Pause("closed"); !errors.Is(err, ErrSchedulerClosed) {
+		t.Errorf("Pause = %v, want ErrSchedulerClosed", err)
+	}
+	if err := ts.Resume("closed", ResumeSkipMissed); !errors.Is(err, ErrSchedulerClosed) {
+		t.Errorf("Resume = %v, want ErrSchedulerClosed", err)
+	}
+	if err := ts.Trigger("closed"); !errors.Is(err, ErrSchedulerClosed) {
+		t.Errorf("Trigger = %v, want ErrSchedulerClosed", err)
+	}
+	if err := ts.SetExecFunc("closed", func() {}); !errors.Is(err, ErrSchedulerClosed) {
+		t.Errorf("SetExecFunc = %v, want ErrSchedulerClosed", err)
+	}
+	if ts.RemoveTask("closed") {
+		t.Error("RemoveTask = true after Close")
+	}
+	if n := ts.taskCount(); n != 0 {
+		t.Errorf("taskCount = %d after Close, want 0", n)
+	}
+}
+
+func TestShutdownCancelsRuns(t *testing.T) {
+	ts := newTestScheduler()
+	started := make(chan struct{})
+	var saved int32
+	if _, err := ts.addTask(newTestTask("checkpoint").Frequently().Minutes(1).ExecFuncCtx(func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			atomic.StoreInt32(&saved, 1)
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "checkpoint")
+	ts.runPending(time.Now())
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	begin := time.Now()
+	if err := ts.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(begin); d > 500*time.Millisecond {
+		t.Errorf("shutdown took %v, want the cancelled run to end right away", d)
+	}
+	if atomic.LoadInt32(&saved) == 0 {
+		t.Error("context of the run in progress is not cancelled")
+	}
+	if err := ts.Shutdown(ctx); !errors.Is(err, ErrSchedulerClosed) {
+		t.Errorf("second shutdown error %v, want ErrSchedulerClosed", err)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	ts := newTestScheduler()
+	started, release := make(chan struct{}), make(chan struct{})
+	if _, err := ts.addTask(newTestTask("stubborn").Frequently().Minutes(1).ExecFunc(func() {
+		close(started)
+		<-release
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "stubborn")
+	ts.runPending(time.Now())
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := ts.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("shutdown error %v, want the deadline with the run still in progress", err)
+	}
+	if ts.taskCount() != 0 {
+		t.Error("tasks are kept after the shutdown")
+	}
+}
+
+func TestRunContextCancelsRuns(t *testing.T) {
+	ts := newTestScheduler()
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	if _, err := ts.addTask(newTestTask("long").Frequently().Minutes(1).ExecFuncCtx(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "long")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ts.RunContext(ctx)
+		close(done)
+	}()
+	<-started
+	cancel()
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("context of the run in progress is not cancelled with the running loop")
+	}
+	<-done
+	ts.inFlight.Wait()
+}
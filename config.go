@@ -0,0 +1,42 @@
+package isked
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SchedulerConfig is the snapshot of the current settings of a task scheduler
+type SchedulerConfig struct {
+	ConflictTolerance time.Duration  // set by 'SetConflictTolerance'
+	GracePeriod       time.Duration  // set by 'SetGracePeriod'
+	BatchWindow       time.Duration  // set by 'SetBatchWindow'
+	LogInterval       time.Duration  // set by 'SetLogInterval'
+	RunWatchdog       time.Duration  // set by 'SetRunWatchdog'
+	ReleaseStuckRuns  bool           // set by 'SetReleaseStuckRuns'
+	ErrorLogWindow    time.Duration  // set by 'SetErrorLogWindow'
+	MaxTasks          int            // set by 'SetMaxTasks', 0 means no limit
+	Maintenance       bool           // set by 'SetMaintenanceMode', regardless of the maintenance check
+	MaintenanceCheck  bool           // true, if a func is set by 'SetMaintenanceCheck'
+	DefaultLocation   *time.Location // set by 'SetDefaultLocation', nil means the local time
+	WeekStart         time.Weekday   // set by 'SetWeekStart'
+}
+
+// Config gets a copy of the current settings of the task scheduler, e.g to verify them at runtime
+func (t *TaskScheduler) Config() SchedulerConfig {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return SchedulerConfig{
+		ConflictTolerance: t.conflictTolerance,
+		GracePeriod:       t.gracePeriod,
+		BatchWindow:       t.batchWindow,
+		LogInterval:       t.logInterval,
+		RunWatchdog:       t.runWatchdog,
+		ReleaseStuckRuns:  t.releaseStuck,
+		ErrorLogWindow:    t.errorLogWindow,
+		MaxTasks:          t.maxTasks,
+		Maintenance:       atomic.LoadInt32(&t.maintenance) == 1,
+		MaintenanceCheck:  t.maintenanceCheck != nil,
+		DefaultLocation:   t.defaultLoc,
+		WeekStart:         t.weekStart,
+	}
+}
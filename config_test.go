@@ -0,0 +1,55 @@
+package isked
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	ts := newTestScheduler()
+	if got := ts.Config(); !reflect.DeepEqual(got, SchedulerConfig{}) {
+		t.Fatalf("default config %+v, want zero", got)
+	}
+
+	loc := loadLocation(t, "Asia/Manila")
+	ts.SetConflictTolerance(time.Second)
+	ts.SetGracePeriod(2 * time.Second)
+	ts.SetBatchWindow(3 * time.Millisecond)
+	ts.SetLogInterval(time.Minute)
+	ts.SetRunWatchdog(time.Hour)
+	ts.SetReleaseStuckRuns(true)
+	ts.SetErrorLogWindow(5 * time.Minute)
+	ts.SetMaxTasks(10)
+	ts.SetMaintenanceMode(true)
+	ts.SetMaintenanceCheck(func() bool { return false })
+	ts.SetDefaultLocation(loc, false)
+
+	want := SchedulerConfig{
+		ConflictTolerance: time.Second,
+		GracePeriod:       2 * time.Second,
+		BatchWindow:       3 * time.Millisecond,
+		LogInterval:       time.Minute,
+		RunWatchdog:       time.Hour,
+		ReleaseStuckRuns:  true,
+		ErrorLogWindow:    5 * time.Minute,
+		MaxTasks:          10,
+		Maintenance:       true,
+		MaintenanceCheck:  true,
+		DefaultLocation:   loc,
+	}
+	got := ts.Config()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("config %+v, want %+v", got, want)
+	}
+
+	// It's a copy, neither side changes the other
+	got.MaxTasks = 99
+	if ts.Config().MaxTasks != 10 {
+		t.Error("changing the snapshot changes the scheduler")
+	}
+	ts.SetMaxTasks(20)
+	if got.MaxTasks != 99 {
+		t.Error("changing the scheduler changes the snapshot")
+	}
+}
@@ -0,0 +1,112 @@
+package isked
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SetConflictTolerance sets how close the next runs of the tasks can be to be reported by
+// the 'Conflicts' method, default is 0 which means the exact same time.
+func (t *TaskScheduler) SetConflictTolerance(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	t.conflictTolerance = d
+}
+
+// Conflicts groups the task names whose next runs are within the conflict tolerance of each other,
+// the groups are sorted by their next run and only groups with at least two tasks are returned.
+func (t *TaskScheduler) Conflicts() [][]string {
+	type nextRun struct {
+		name string
+		at   time.Time
+	}
+
+	t.mu.RLock()
+	tolerance := t.conflictTolerance
+	var runs []nextRun
+	for _, e := range t.TaskList {
+		for _, s := range e {
+			if !s.nextRunTime.IsZero() {
+				runs = append(runs, nextRun{name: s.Name, at: s.nextRunTime})
+			}
+		}
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].at.Equal(runs[j].at) {
+			return runs[i].name < runs[j].name
+		}
+		return runs[i].at.Before(runs[j].at)
+	})
+
+	var conflicts [][]string
+	for i := 0; i < len(runs); {
+		// Group all the tasks within the tolerance of the first one in the group
+		j := i + 1
+		for j < len(runs) && runs[j].at.Sub(runs[i].at) <= tolerance {
+			j++
+		}
+		if j-i > 1 {
+			group := make([]string, 0, j-i)
+			for _, e := range runs[i:j] {
+				group = append(group, e.name)
+			}
+			conflicts = append(conflicts, group)
+		}
+		i = j
+	}
+	return conflicts
+}
+
+// FindDuplicateRules groups the task names that have the exact same rule, i.e the run type, interval, 'At'
+// time, day and timezone, e.g a task that is registered twice by mistake. The groups are sorted by their
+// first task name and only groups with at least two tasks are returned. Tasks that use the 'NextFunc'
+// method are not checked.
+func (t *TaskScheduler) FindDuplicateRules() [][]string {
+	t.mu.RLock()
+	rules := make(map[string][]string)
+	for _, e := range t.TaskList {
+		for i := range e {
+			if e[i].nextFunc == nil {
+				key := e[i].ruleKey()
+				rules[key] = append(rules[key], e[i].Name)
+			}
+		}
+	}
+	t.mu.RUnlock()
+
+	var duplicates [][]string
+	for _, names := range rules {
+		if len(names) > 1 {
+			sort.Strings(names)
+			duplicates = append(duplicates, names)
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i][0] < duplicates[j][0] })
+	return duplicates
+}
+
+// ruleKey returns the rule of the task as a comparable key
+func (s *Tasks) ruleKey() string {
+	def := s.def()
+	key := fmt.Sprintf("%s|%v|%d|%d|%s|%s", def.RunType, def.Interval, def.Weekday, def.MonthDay, def.At, s.location())
+	if s.hasPhase || s.epochAligned {
+		key += fmt.Sprintf("|phase=%v,epoch=%t", s.phase, s.epochAligned)
+	}
+	if s.weekOfMonth > 0 {
+		key += fmt.Sprintf("|week=%d,start=%v", s.weekOfMonth, s.weekStart)
+	}
+	if s.RunType == _onetime {
+		// The onetime option runs on its DateTime, the first one is the next run
+		key += "|" + s.nextRunTime.Format(time.RFC3339Nano)
+		for _, d := range s.dates {
+			key += "," + d.Format(time.RFC3339Nano)
+		}
+	}
+	return key
+}
@@ -0,0 +1,81 @@
+package isked
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConflicts(t *testing.T) {
+	ts := newTestScheduler()
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	for name, dt := range map[string]time.Time{
+		"backup":  at,
+		"export":  at,
+		"cleanup": at.Add(20 * time.Second),
+		"report":  at.Add(time.Hour),
+	} {
+		if _, err := ts.addTask(newTestTask(name).OneTime(dt.Unix()).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, want := ts.Conflicts(), [][]string{{"backup", "export"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("exact conflicts %v, want %v", got, want)
+	}
+	ts.SetConflictTolerance(30 * time.Second)
+	if got, want := ts.Conflicts(), [][]string{{"backup", "export", "cleanup"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("conflicts within 30s %v, want %v", got, want)
+	}
+}
+
+func TestNoConflicts(t *testing.T) {
+	ts := newTestScheduler()
+	at := time.Now().Add(time.Hour)
+	for i, name := range []string{"a", "b", "c"} {
+		dt := at.Add(time.Duration(i) * time.Minute)
+		if _, err := ts.addTask(newTestTask(name).OneTime(dt.Unix()).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts.SetConflictTolerance(30 * time.Second)
+	if got := ts.Conflicts(); len(got) != 0 {
+		t.Errorf("conflicts %v, want none", got)
+	}
+}
+
+func TestFindDuplicateRules(t *testing.T) {
+	ts := newTestScheduler()
+	loc := loadLocation(t, "Asia/Manila")
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	for _, s := range []*Tasks{
+		newTestTask("report").Daily().At("09:00"),
+		newTestTask("report-copy").Daily().At("09:00"),
+		newTestTask("report-manila").Daily().At("09:00").In(loc),
+		newTestTask("report-later").Daily().At("09:30"),
+		newTestTask("poll").Frequently().Minutes(5),
+		newTestTask("poll-again").Frequently().Minutes(5),
+		newTestTask("poll-other").Frequently().Seconds(300).Phase(time.Minute),
+		newTestTask("weekly-mon").Weekly().Monday().At("09:00"),
+		newTestTask("weekly-tue").Weekly().Tuesday().At("09:00"),
+		newTestTask("remind").OneTime(at.Unix()),
+		newTestTask("remind-later").OneTime(at.Add(time.Minute).Unix()),
+		newTestTask("custom").NextFunc(func() time.Duration { return 5 * time.Minute }),
+		newTestTask("custom-too").NextFunc(func() time.Duration { return 5 * time.Minute }),
+	} {
+		if _, err := ts.addTask(s.ExecFunc(func() {})); err != nil {
+			t.Fatalf("%s: %v", s.Name, err)
+		}
+	}
+
+	want := [][]string{{"poll", "poll-again"}, {"report", "report-copy"}}
+	if got := ts.FindDuplicateRules(); !reflect.DeepEqual(got, want) {
+		t.Errorf("duplicates %v, want %v", got, want)
+	}
+
+	ts.RemoveTask("poll-again")
+	ts.RemoveTask("report-copy")
+	if got := ts.FindDuplicateRules(); len(got) != 0 {
+		t.Errorf("duplicates %v with distinct rules, want none", got)
+	}
+}
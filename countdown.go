@@ -0,0 +1,33 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+)
+
+// ScheduleBefore adds a onetime task for each offset before the event, e.g the reminders at 24 hours,
+// 1 hour and 15 minutes before it. Each task is named after its offset, e.g "Launch T-1h0m0s", and the
+// offsets that are already in the past are skipped. It returns the errors of the tasks that are not added.
+func (t *TaskScheduler) ScheduleBefore(taskName string, event time.Time, offsets []time.Duration, fn FuncToExecMeta) []error {
+	if fn == nil {
+		return []error{fmt.Errorf("%s: missing function to execute", taskName)}
+	}
+	var errs []error
+	now := time.Now()
+	for _, offset := range offsets {
+		at := event.Add(-offset)
+		if !at.After(now) {
+			continue
+		}
+		s := &Tasks{
+			Name:      t.uniqueName(fmt.Sprintf("%s T-%v", taskName, offset)),
+			monthName: time.Now().Local().Month(),
+			created:   time.Now(),
+		}
+		s.OneTime(at.Unix()).ExecFuncMeta(fn)
+		if _, err := t.addTask(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,48 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleBefore(t *testing.T) {
+	ts := newTestScheduler()
+	event := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	offsets := []time.Duration{24 * time.Hour, time.Hour, 15 * time.Minute}
+	var fired []RunMeta
+	errs := ts.ScheduleBefore("Launch", event, offsets, func(meta RunMeta) error {
+		fired = append(fired, meta)
+		return nil
+	})
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	// T-24h is already in the past
+	if _, ok := ts.Get("Launch T-24h0m0s"); ok {
+		t.Error("reminder in the past is added")
+	}
+	want := map[string]time.Time{
+		"Launch T-1h0m0s": event.Add(-time.Hour),
+		"Launch T-15m0s":  event.Add(-15 * time.Minute),
+	}
+	if len(ts.TaskList) != len(want) {
+		t.Errorf("%d reminders, want %d", len(ts.TaskList), len(want))
+	}
+	for name, at := range want {
+		info, ok := ts.Info(name)
+		if !ok || info.RunType != RunOneTime || !info.NextRun.Equal(at) {
+			t.Errorf("%s: %+v, want a onetime run at %v", name, info, at)
+		}
+	}
+
+	makeDue(t, ts, "Launch T-1h0m0s")
+	ts.RunPending()
+	if len(fired) != 1 || fired[0].Name != "Launch T-1h0m0s" {
+		t.Errorf("fired %v, want the T-1h reminder", fired)
+	}
+
+	if errs := ts.ScheduleBefore("Missing", event, offsets, nil); len(errs) != 1 {
+		t.Errorf("errors %v without a func, want one", errs)
+	}
+}
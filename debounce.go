@@ -0,0 +1,57 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Debounce method coalesces the triggers of the task from the 'Trigger' method, the task runs once there's
+// no other trigger within the duration since the last one, e.g to run once after a burst of file changes.
+func (s *Tasks) Debounce(d time.Duration) *Tasks {
+	if d <= 0 {
+		s.setErr("Debounce", fmt.Errorf("invalid debounce duration %v", d))
+		return s
+	}
+	s.debounce = d
+	return s
+}
+
+// Trigger runs the task using the task name or the task ID right away regardless of its schedule, or once
+// there's no other trigger within its 'Debounce' duration. The task follows its schedule as usual after the run,
+// the onetime tasks can't be triggered since it would take the place of their scheduled run.
+func (t *TaskScheduler) Trigger(taskName string) error {
+	if t.isClosed() {
+		return ErrSchedulerClosed
+	}
+	t.mu.Lock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		t.mu.Unlock()
+		return fmt.Errorf("%s: %w", taskName, ErrTaskNotFound)
+	}
+	s := &taskData[0]
+	if s.RunType == _onetime {
+		// Its only run would be used up by the trigger
+		t.mu.Unlock()
+		return fmt.Errorf("%s: onetime tasks can't be triggered", taskName)
+	}
+	s.nextRunTime = time.Now().Add(s.debounce)
+	t.emit(ChangeRescheduled, s)
+	logNextSched := t.allowLog(s)
+	nextSchedToRun := s.nextRunTime
+	kv := s.logKV()
+	name := s.Name
+	t.mu.Unlock()
+	t.notify()
+
+	if !logNextSched {
+		return nil
+	}
+	nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
+	msg := name + " is triggered, next schedule to run on: " + nextSched
+	logger().Infow(msg, kv...)
+	color.Cyan(msg)
+	return nil
+}
@@ -0,0 +1,75 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDebounce(t *testing.T) {
+	ts := newTestScheduler()
+	runs := 0
+	if _, err := ts.addTask(newTestTask("reindex").Frequently().Hours(1).Debounce(100 * time.Millisecond).ExecFunc(func() { runs++ })); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each trigger of the burst pushes the run back
+	for i := 0; i < 5; i++ {
+		if err := ts.Trigger("reindex"); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(30 * time.Millisecond)
+		ts.RunPending()
+	}
+	if runs != 0 {
+		t.Fatalf("%d runs during the burst, want 0", runs)
+	}
+
+	time.Sleep(120 * time.Millisecond)
+	ts.RunPending()
+	ts.RunPending()
+	if runs != 1 {
+		t.Fatalf("%d runs after the burst, want 1 coalesced run", runs)
+	}
+	if info, _ := ts.Info("reindex"); time.Until(info.NextRun) < 59*time.Minute {
+		t.Errorf("next run %v after the coalesced run, want back on the hourly schedule", info.NextRun)
+	}
+
+	if err := ts.Trigger("missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("trigger error %v, want ErrTaskNotFound", err)
+	}
+	if _, err := ts.addTask(newTestTask("invalid").Frequently().Hours(1).Debounce(0).ExecFunc(func() {})); err == nil {
+		t.Error("task with a zero debounce is added")
+	}
+}
+
+func TestTriggerWithoutDebounce(t *testing.T) {
+	ts := newTestScheduler()
+	runs := 0
+	if _, err := ts.addTask(newTestTask("now").Frequently().Hours(1).ExecFunc(func() { runs++ })); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Trigger("now"); err != nil {
+		t.Fatal(err)
+	}
+	ts.RunPending()
+	if runs != 1 {
+		t.Errorf("%d runs after the trigger, want 1 right away", runs)
+	}
+}
+
+func TestTriggerOneTime(t *testing.T) {
+	ts := newTestScheduler()
+	runs := 0
+	if _, err := ts.addTask(newTestTask("once").OneTime(time.Now().Add(time.Hour).Unix()).ExecFunc(func() { runs++ })); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := ts.Info("once")
+	if err := ts.Trigger("once"); err == nil {
+		t.Error("onetime task is triggered")
+	}
+	ts.RunPending()
+	if info, ok := ts.Info("once"); runs != 0 || !ok || !info.NextRun.Equal(before.NextRun) {
+		t.Errorf("%d runs with next run %v, want its scheduled run %v kept", runs, info.NextRun, before.NextRun)
+	}
+}
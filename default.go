@@ -0,0 +1,75 @@
+package isked
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// errDefaultReset is the cause of stopping the running loop of the default task scheduler
+var errDefaultReset = errors.New("default task scheduler is reset")
+
+// ResetDefault tears down the default task scheduler 'TS' so it starts fresh, e.g between tests. It stops
+// its running loop, cancels the contexts of the runs that are in progress and waits for them, then removes
+// all the tasks and namespaces, closes the watch channels and restores the default settings. 'Run' can be
+// called again after it.
+func ResetDefault() {
+	TS.stop(errDefaultReset)
+	for atomic.LoadInt32(&TS.running) == 1 {
+		time.Sleep(time.Millisecond)
+	}
+	all := TS.withNamespaces()
+	TS.cancelRunsContext()
+	waitRuns(all)
+	removeAll(all)
+
+	TS.mu.Lock()
+	TS.namespaces = nil
+	TS.active = nil
+	TS.conflictTolerance = 0
+	TS.gracePeriod = 0
+	TS.batchWindow = 0
+	TS.logInterval = 0
+	TS.runWatchdog = 0
+	TS.releaseStuck = false
+	TS.errorLogWindow = 0
+	TS.maxTasks = 0
+	TS.panicPolicy = PanicRecover
+	TS.maintenanceCheck = nil
+	TS.defaultLoc = nil
+	TS.weekStart = time.Sunday
+	TS.mu.Unlock()
+
+	TS.loopMu.Lock()
+	TS.loop = LoopStats{}
+	TS.executions = 0
+	TS.loopMu.Unlock()
+
+	TS.watchMu.Lock()
+	TS.droppedChanges = 0
+	TS.onDropped = nil
+	TS.watchMu.Unlock()
+
+	// Drop any pending signal so the next running loop doesn't stop or wake up right away
+	select {
+	case <-TS.haltChannel():
+	default:
+	}
+	select {
+	case <-TS.wakeChannel():
+	default:
+	}
+	select {
+	case <-ChannelTS:
+	default:
+	}
+	atomic.StoreInt32(&TS.closed, 0)
+	atomic.StoreInt32(&TS.maintenance, 0)
+	TK = Tasks{}
+
+	msg := "default task scheduler is reset"
+	logger().Warnw(msg, "log_time", time.Now().Format(logDateTimeFormat))
+	color.Yellow(msg)
+}
@@ -0,0 +1,110 @@
+package isked
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResetDefault(t *testing.T) {
+	t.Cleanup(ResetDefault)
+	if _, err := newTestTask("reset-task").Frequently().Minutes(1).ExecFunc(func() {}).Add(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TS.Namespace("reset-ns").AddTask(newTestTask("reset-task").Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	TS.SetGracePeriod(time.Second)
+	TS.SetMaxTasks(5)
+	TS.SetMaintenanceMode(true)
+	TS.SetOnDroppedEvent(func(int) {})
+
+	stopped := make(chan struct{})
+	go func() {
+		Run()
+		close(stopped)
+	}()
+	waitUntil(t, time.Second, func() bool { return atomic.LoadInt32(&TS.running) == 1 })
+
+	ResetDefault()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("running loop is not stopped by ResetDefault")
+	}
+
+	TS.mu.RLock()
+	tasks, namespaces := len(TS.TaskList), len(TS.namespaces)
+	grace, maxTasks := TS.gracePeriod, TS.maxTasks
+	TS.mu.RUnlock()
+	TS.watchMu.Lock()
+	onDropped := TS.onDropped
+	TS.watchMu.Unlock()
+	if tasks != 0 || namespaces != 0 {
+		t.Errorf("%d tasks and %d namespaces left, want none", tasks, namespaces)
+	}
+	if grace != 0 || maxTasks != 0 || TS.inMaintenance() || onDropped != nil {
+		t.Error("settings are not restored to their defaults")
+	}
+	if st := TS.LoopStats(); st.Iterations != 0 || st.Executions != 0 {
+		t.Errorf("loop stats %+v, want zero", st)
+	}
+
+	// A clean slate, the same task name can be added and the loop runs again
+	if _, err := newTestTask("reset-task").Frequently().Minutes(1).ExecFunc(func() {}).Add(); err != nil {
+		t.Fatalf("task is not added after ResetDefault: %v", err)
+	}
+	again := make(chan struct{})
+	go func() {
+		Run()
+		close(again)
+	}()
+	waitUntil(t, time.Second, func() bool { return atomic.LoadInt32(&TS.running) == 1 })
+	ChannelTS <- true
+	<-again
+}
+
+func TestResetDefaultReleasesRuns(t *testing.T) {
+	t.Cleanup(ResetDefault)
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	if _, err := newTestTask("reset-long").Frequently().Minutes(1).ExecFuncCtx(func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			close(cancelled)
+		case <-time.After(5 * time.Second):
+		}
+		return ctx.Err()
+	}).Add(); err != nil {
+		t.Fatal(err)
+	}
+	changes := TS.Watch()
+	makeDue(t, &TS, "reset-long")
+	TS.runPending(time.Now())
+	<-started
+
+	ResetDefault()
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("context of the run in progress is not cancelled")
+	}
+	TS.mu.RLock()
+	running := len(TS.active)
+	TS.mu.RUnlock()
+	if running != 0 || len(TS.Running()) != 0 {
+		t.Errorf("%d runs still listed as in progress", running)
+	}
+	// The buffered changes are drained, then the channel must be closed
+	for {
+		select {
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+		case <-time.After(time.Second):
+			t.Fatal("watch channel is not closed")
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+)
+
+// Describe gets the human-readable schedule of the task using the task name or the task ID,
+// e.g "Every 15 minutes" or "Weekly on Monday at 17:00".
+func (t *TaskScheduler) Describe(taskName string) (string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		return "", fmt.Errorf("%s: %w", taskName, ErrTaskNotFound)
+	}
+	return taskData[0].describe(), nil
+}
+
+// describe returns the human-readable schedule of the task
+func (s *Tasks) describe() string {
+	at := s.runAtHour + ":" + s.runAtMinute
+	var desc string
+	switch {
+	case s.nextAt != nil || s.nextFunc != nil:
+		desc = "Custom schedule"
+	case s.RunType == _onetime && s.onDates:
+		desc = "Once on " + describeDateTime(s.nextRunTime)
+		if len(s.dates) > 0 {
+			desc += fmt.Sprintf(" and %d more dates", len(s.dates))
+		}
+	case s.RunType == _onetime && s.isNextWeekday:
+		desc = "Once on the next " + s.dayName.String() + " at " + at
+	case s.RunType == _onetime:
+		desc = "Once on " + describeDateTime(s.nextRunTime)
+	case s.RunType == _frequently:
+		desc = "Every " + describeInterval(s.interval())
+		if s.epochAligned {
+			desc += " since the Unix epoch"
+		}
+		if s.hasPhase {
+			desc += fmt.Sprintf(" with a phase of %v", s.phase)
+		}
+	case s.RunType == _daily:
+		desc = "Daily at " + at
+	case s.RunType == _weekly:
+		desc = "Weekly on " + s.dayName.String() + " at " + at
+		if s.weekOfMonth > 0 {
+			desc = fmt.Sprintf("Weekly on %s of week %d at %s", s.dayName, s.weekOfMonth, at)
+		}
+	case s.RunType == _monthly:
+		desc = fmt.Sprintf("Monthly on day %d at %s", s.runDay(), at)
+	default:
+		return "Unknown schedule"
+	}
+	if loc := s.location(); loc != time.Local && (s.isRunAt || s.RunType == _onetime) {
+		desc += " (" + loc.String() + ")"
+	}
+	return desc
+}
+
+// describeInterval returns the interval in its largest whole unit, e.g "15 minutes" or "hour"
+func describeInterval(d time.Duration) string {
+	n, unit := int64(d/time.Second), "second"
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		n, unit = int64(d/time.Hour), "hour"
+	case d >= time.Minute && d%time.Minute == 0:
+		n, unit = int64(d/time.Minute), "minute"
+	}
+	if n == 1 {
+		return unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// describeDateTime formats the DateTime for the description
+func describeDateTime(dt time.Time) string {
+	v, _ := formatDT(dt, logDateTimeFormat)
+	return v
+}
@@ -0,0 +1,47 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	ts := newTestScheduler()
+	at := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	tests := []struct {
+		task *Tasks
+		want string
+	}{
+		{newTestTask("seconds").Frequently().Seconds(1), "Every second"},
+		{newTestTask("minutes").Frequently().Minutes(15), "Every 15 minutes"},
+		{newTestTask("combined").Frequently().Hours(1).Minutes(30), "Every 90 minutes"},
+		{newTestTask("phased").Frequently().Hours(1).Phase(17 * time.Minute), "Every hour with a phase of 17m0s"},
+		{newTestTask("daily").Daily().At("09:00"), "Daily at 09:00"},
+		{newTestTask("weekly").Weekly().Monday().At("17:00"), "Weekly on Monday at 17:00"},
+		{newTestTask("monthly").Monthly().Every(15).At("08:30"), "Monthly on day 15 at 08:30"},
+		{newTestTask("onetime").OneTime(at.Unix()), "Once on " + describeDateTime(at)},
+		{newTestTask("dates").OnDates(at, at.Add(time.Hour), at.Add(2*time.Hour)), "Once on " + describeDateTime(at) + " and 2 more dates"},
+		{newTestTask("next-weekday").NextWeekday(time.Friday).At("17:00"), "Once on the next Friday at 17:00"},
+		{newTestTask("custom").NextFunc(func() time.Duration { return time.Hour }), "Custom schedule"},
+	}
+	for _, tt := range tests {
+		if _, err := ts.addTask(tt.task.ExecFunc(func() {})); err != nil {
+			t.Fatalf("%s: %v", tt.task.Name, err)
+		}
+		if got, err := ts.Describe(tt.task.Name); err != nil || got != tt.want {
+			t.Errorf("%s: Describe = %q, %v, want %q", tt.task.Name, got, err, tt.want)
+		}
+	}
+
+	loc := loadLocation(t, "Asia/Manila")
+	if _, err := ts.addTask(newTestTask("manila").Daily().At("09:00").In(loc).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ts.Describe("manila"); got != "Daily at 09:00 (Asia/Manila)" {
+		t.Errorf("Describe = %q, want the timezone", got)
+	}
+	if _, err := ts.Describe("missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("missing task error %v, want ErrTaskNotFound", err)
+	}
+}
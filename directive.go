@@ -0,0 +1,80 @@
+package isked
+
+import (
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// RunDirective is returned by the 'ExecFuncErr' function to change its own schedule,
+// use the 'Reschedule', 'SkipNext' or 'StopTask' functions to create it.
+type RunDirective struct {
+	after    time.Duration
+	skipNext bool
+	stop     bool
+}
+
+// Error implements the error interface so the directive can be returned by the 'ExecFuncErr' function
+func (d *RunDirective) Error() string {
+	switch {
+	case d.stop:
+		return "stop the task"
+	case d.skipNext:
+		return "skip the next run"
+	default:
+		return "run again after " + d.after.String()
+	}
+}
+
+// Reschedule runs the task again after the given duration instead of its regular next run
+func Reschedule(after time.Duration) error {
+	return &RunDirective{after: after}
+}
+
+// SkipNext skips the regular next run of the task, it runs again on the schedule after it
+func SkipNext() error {
+	return &RunDirective{skipNext: true}
+}
+
+// StopTask removes the task from the task list, the current run is its last run
+func StopTask() error {
+	return &RunDirective{stop: true}
+}
+
+// applyDirective changes the next run of the task as directed by its own function
+func (t *TaskScheduler) applyDirective(s *Tasks, d *RunDirective) {
+	if d.stop {
+		if t.endTask(s, EndStopped, "as directed by its function") && s.onEnd != nil {
+			s.onEnd(EndStopped)
+		}
+		return
+	}
+
+	t.mu.Lock()
+	cur, ok := t.TaskList[s.Name]
+	if !ok || len(cur) == 0 {
+		t.mu.Unlock()
+		return
+	}
+
+	switch {
+	case d.skipNext:
+		cur[0].nextRunTime = cur[0].nextSchedule(cur[0].nextRunTime)
+		t.emit(ChangeRescheduled, &cur[0])
+	case d.after > 0:
+		cur[0].nextRunTime = time.Now().Add(d.after)
+		t.emit(ChangeRescheduled, &cur[0])
+	}
+	logNextSched := t.allowLog(&cur[0])
+	nextSchedToRun := cur[0].nextRunTime
+	t.mu.Unlock()
+	t.notify()
+
+	if !logNextSched {
+		return
+	}
+	nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
+	msg := s.Name + " next schedule to run on: " + nextSched
+	logger().Infow(msg, s.logKV()...)
+	color.Magenta(msg)
+}
@@ -0,0 +1,54 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+// runDirected adds the frequently task that returns the directive and runs it once
+func runDirected(t *testing.T, ts *TaskScheduler, name string, directive error) {
+	t.Helper()
+	if _, err := ts.addTask(newTestTask(name).Frequently().Minutes(1).ExecFuncErr(func() error { return directive })); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, name)
+	ts.RunPending()
+}
+
+func TestReschedule(t *testing.T) {
+	ts := newTestScheduler()
+	runDirected(t, ts, "poll", Reschedule(20*time.Minute))
+	info, _ := ts.Info("poll")
+	if d := time.Until(info.NextRun); d < 19*time.Minute || d > 20*time.Minute {
+		t.Errorf("next run in %v, want 20m as directed", d)
+	}
+	if info.Stats.Runs != 1 || info.Stats.Errors != 0 {
+		t.Errorf("stats %+v, the directive is not an error", info.Stats)
+	}
+}
+
+func TestSkipNext(t *testing.T) {
+	ts := newTestScheduler()
+	runDirected(t, ts, "poll", SkipNext())
+	info, _ := ts.Info("poll")
+	if d := time.Until(info.NextRun); d < 119*time.Second || d > 2*time.Minute {
+		t.Errorf("next run in %v, want 2m after skipping the next run", d)
+	}
+}
+
+func TestStopTask(t *testing.T) {
+	ts := newTestScheduler()
+	var reason string
+	if _, err := ts.addTask(newTestTask("once").Frequently().Minutes(1).OnEnd(func(r string) { reason = r }).
+		ExecFuncErr(func() error { return StopTask() })); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "once")
+	ts.RunPending()
+	if _, ok := ts.Get("once"); ok {
+		t.Error("task is not stopped")
+	}
+	if reason != EndStopped {
+		t.Errorf("end reason %q, want %q", reason, EndStopped)
+	}
+}
@@ -0,0 +1,146 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse adds the task from a compact schedule spec, it returns an error if the spec is invalid or
+// the task name is already in use. The supported forms are:
+//
+//	every 15m                   frequently, any whole seconds duration e.g "30s", "1h30m"
+//	daily at 09:00
+//	weekly on monday at 17:00
+//	monthly on 1 at 00:00       use "last" for the last day of the month
+func (t *TaskScheduler) Parse(name, spec string, fn FuncToExec) error {
+	if len(name) == 0 {
+		return errors.New("missing task name")
+	}
+	opts, err := parseSpec(spec)
+	if err != nil {
+		return err
+	}
+	opts.Name = name
+	opts.ExecuteFunc = fn
+
+	s, err := opts.newTask()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	s.Name = name
+	_, err = t.addTask(s)
+	return err
+}
+
+// parseSpec converts the schedule spec to the schedule options without the name and func
+func parseSpec(spec string) (ScheduleOptions, error) {
+	var opts ScheduleOptions
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 {
+		return opts, errors.New("empty schedule spec")
+	}
+
+	// expect checks the keyword at the position of the fields
+	expect := func(i int, keyword string) error {
+		if i >= len(fields) || fields[i] != keyword {
+			return fmt.Errorf("invalid schedule spec %q, expected %q at word %d", spec, keyword, i+1)
+		}
+		return nil
+	}
+
+	n := 0
+	switch fields[0] {
+	case "every":
+		if len(fields) < 2 {
+			return opts, fmt.Errorf("invalid schedule spec %q, missing the interval", spec)
+		}
+		d, err := time.ParseDuration(fields[1])
+		if err != nil {
+			return opts, fmt.Errorf("invalid schedule spec %q, bad interval %q", spec, fields[1])
+		}
+		opts.RunType = RunFrequently
+		opts.Interval = d
+		n = 2
+
+	case "daily":
+		if err := expect(1, "at"); err != nil {
+			return opts, err
+		}
+		if len(fields) < 3 {
+			return opts, fmt.Errorf("invalid schedule spec %q, missing the 'at' time", spec)
+		}
+		opts.RunType = RunDaily
+		opts.At = fields[2]
+		n = 3
+
+	case "weekly":
+		if err := expect(1, "on"); err != nil {
+			return opts, err
+		}
+		if len(fields) < 3 {
+			return opts, fmt.Errorf("invalid schedule spec %q, missing the weekday", spec)
+		}
+		wd, ok := parseWeekday(fields[2])
+		if !ok {
+			return opts, fmt.Errorf("invalid schedule spec %q, bad weekday %q", spec, fields[2])
+		}
+		if err := expect(3, "at"); err != nil {
+			return opts, err
+		}
+		if len(fields) < 5 {
+			return opts, fmt.Errorf("invalid schedule spec %q, missing the 'at' time", spec)
+		}
+		opts.RunType = RunWeekly
+		opts.Weekday = wd
+		opts.At = fields[4]
+		n = 5
+
+	case "monthly":
+		if err := expect(1, "on"); err != nil {
+			return opts, err
+		}
+		if len(fields) < 3 {
+			return opts, fmt.Errorf("invalid schedule spec %q, missing the month day", spec)
+		}
+		day := 0
+		if fields[2] != "last" {
+			d, err := strconv.Atoi(fields[2])
+			if err != nil || d < 1 || d > 31 {
+				return opts, fmt.Errorf("invalid schedule spec %q, bad month day %q", spec, fields[2])
+			}
+			day = d
+		}
+		if err := expect(3, "at"); err != nil {
+			return opts, err
+		}
+		if len(fields) < 5 {
+			return opts, fmt.Errorf("invalid schedule spec %q, missing the 'at' time", spec)
+		}
+		opts.RunType = RunMonthly
+		opts.MonthDay = day
+		opts.At = fields[4]
+		n = 5
+
+	default:
+		return opts, fmt.Errorf("invalid schedule spec %q, unknown form %q", spec, fields[0])
+	}
+
+	if len(fields) > n {
+		return opts, fmt.Errorf("invalid schedule spec %q, unexpected %q", spec, strings.Join(fields[n:], " "))
+	}
+	return opts, nil
+}
+
+// parseWeekday converts the full or three letter weekday name to its time.Weekday
+func parseWeekday(v string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if v == name || v == name[:3] {
+			return d, true
+		}
+	}
+	return time.Sunday, false
+}
@@ -0,0 +1,76 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want ScheduleOptions
+	}{
+		{"every 15m", ScheduleOptions{RunType: RunFrequently, Interval: 15 * time.Minute}},
+		{"every 1h30m", ScheduleOptions{RunType: RunFrequently, Interval: 90 * time.Minute}},
+		{"daily at 09:00", ScheduleOptions{RunType: RunDaily, At: "09:00"}},
+		{"Weekly on Monday at 17:00", ScheduleOptions{RunType: RunWeekly, Weekday: time.Monday, At: "17:00"}},
+		{"weekly on fri at 08:30", ScheduleOptions{RunType: RunWeekly, Weekday: time.Friday, At: "08:30"}},
+		{"monthly on 1 at 00:00", ScheduleOptions{RunType: RunMonthly, MonthDay: 1, At: "00:00"}},
+		{"monthly on last at 23:00", ScheduleOptions{RunType: RunMonthly, MonthDay: 0, At: "23:00"}},
+	}
+	for _, tt := range tests {
+		got, err := parseSpec(tt.spec)
+		if err != nil {
+			t.Errorf("%q: %v", tt.spec, err)
+			continue
+		}
+		if got.RunType != tt.want.RunType || got.Interval != tt.want.Interval || got.Weekday != tt.want.Weekday ||
+			got.MonthDay != tt.want.MonthDay || got.At != tt.want.At {
+			t.Errorf("%q: %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseSpecMalformed(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"every",
+		"every soon",
+		"daily",
+		"daily 09:00",
+		"weekly on funday at 09:00",
+		"weekly on monday",
+		"monthly on 32 at 09:00",
+		"monthly on first at 09:00",
+		"hourly at 15",
+		"daily at 09:00 and 17:00",
+	} {
+		if _, err := parseSpec(spec); err == nil {
+			t.Errorf("%q: no parse error", spec)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	ts := newTestScheduler()
+	if err := ts.Parse("report", "weekly on monday at 17:00", func() {}); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := ts.Info("report")
+	if at := info.NextRun.In(time.Local); info.RunType != RunWeekly || at.Weekday() != time.Monday || at.Hour() != 17 {
+		t.Errorf("%s task next run %v, want weekly on Monday at 17:00", info.RunType, at)
+	}
+
+	if err := ts.Parse("report", "daily at 09:00", func() {}); !errors.Is(err, ErrTaskExists) {
+		t.Errorf("error %v, want ErrTaskExists", err)
+	}
+	for _, spec := range []string{"daily at 25:00", "every 500ms"} {
+		if err := ts.Parse("bad", spec, func() {}); err == nil {
+			t.Errorf("%q: no error for an invalid value", spec)
+		}
+	}
+	if err := ts.Parse("", "daily at 09:00", func() {}); err == nil {
+		t.Error("no error for the missing name")
+	}
+}
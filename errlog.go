@@ -0,0 +1,34 @@
+package isked
+
+import "time"
+
+// SetErrorLogWindow collapses the same error of a task that is repeated within the window into a single
+// "repeated n times" message, it's logged once a different error, a success or the end of the window comes.
+// Default is 0 which logs every error.
+func (t *TaskScheduler) SetErrorLogWindow(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	t.errorLogWindow = d
+}
+
+// dedupeError checks if the error of the run can be logged, it returns how many times the previous error
+// was repeated when it's time to report it. The lock of the task list must be held.
+func (t *TaskScheduler) dedupeError(s *Tasks, err error) (bool, int) {
+	now := time.Now()
+	if err != nil && t.errorLogWindow > 0 && err.Error() == s.errLogMsg && now.Sub(s.errLogAt) < t.errorLogWindow {
+		s.errRepeats++
+		return false, 0
+	}
+
+	repeated := s.errRepeats
+	s.errRepeats = 0
+	s.errLogMsg = ""
+	if err != nil {
+		s.errLogMsg = err.Error()
+		s.errLogAt = now
+	}
+	return true, repeated
+}
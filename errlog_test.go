@@ -0,0 +1,48 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestErrorLogWindow(t *testing.T) {
+	for _, window := range []time.Duration{0, time.Minute} {
+		logs := useRecordLogger(t)
+		ts := newTestScheduler()
+		ts.SetErrorLogWindow(window)
+		var result error
+		if _, err := ts.addTask(newTestTask("flaky").Frequently().Minutes(1).ExecFuncErr(func() error { return result })); err != nil {
+			t.Fatal(err)
+		}
+		run := func(err error, times int) {
+			result = err
+			for i := 0; i < times; i++ {
+				makeDue(t, ts, "flaky")
+				ts.RunPending()
+			}
+		}
+
+		run(errors.New("connection refused"), 5)
+		want := 5
+		if window > 0 {
+			want = 1
+		}
+		if n := logs.count("returns an error: connection refused"); n != want {
+			t.Errorf("window %v: %d error logs for 5 identical errors, want %d", window, n, want)
+		}
+
+		// The count is reported once the errors stop
+		run(nil, 1)
+		if n := logs.count("the previous error is repeated 4 times"); (window > 0) != (n == 1) {
+			t.Errorf("window %v: %d repeated messages after the success", window, n)
+		}
+
+		// Different errors are never collapsed
+		run(errors.New("timeout"), 1)
+		run(errors.New("bad gateway"), 1)
+		if logs.count("returns an error: timeout") != 1 || logs.count("returns an error: bad gateway") != 1 {
+			t.Errorf("window %v: different errors are not all logged", window)
+		}
+	}
+}
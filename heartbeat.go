@@ -0,0 +1,35 @@
+package isked
+
+import (
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Heartbeat adds a frequently task that only logs its run, use it as a liveness beacon or to check that
+// the running loop is firing. It returns the final task name like 'Schedule', its last run is available
+// with the 'Info' method, or the error if it's not added, e.g an interval below 1 second.
+func (t *TaskScheduler) Heartbeat(name string, interval time.Duration) (string, error) {
+	opts := ScheduleOptions{
+		Name:        name,
+		RunType:     RunFrequently,
+		Interval:    interval,
+		ExecuteFunc: func() {},
+	}
+	s, err := opts.newTask()
+	if err != nil {
+		return "", err
+	}
+	s.Name = t.uniqueName(name)
+	// The log uses the final name and the ID of the stored task, so it matches one task even if the names clash
+	s.ExecFuncMeta(func(meta RunMeta) error {
+		msg := "heartbeat: " + meta.Name
+		logger().Infow(msg, "log_time", time.Now().Format(logDateTimeFormat), "task_id", meta.ID)
+		color.Green(msg)
+		return nil
+	})
+	if _, err := t.addTask(s); err != nil {
+		return "", err
+	}
+	return s.Name, nil
+}
@@ -0,0 +1,56 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeartbeat(t *testing.T) {
+	ts := newTestScheduler()
+	name, err := ts.Heartbeat("beacon", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, ok := ts.Info(name)
+	if !ok || info.Interval != time.Minute {
+		t.Errorf("heartbeat %q is not added every minute", name)
+	}
+
+	if _, err := ts.Heartbeat("too-fast", time.Millisecond); err == nil {
+		t.Error("no error for an interval below 1 second")
+	}
+	if _, ok := ts.Get("too-fast"); ok {
+		t.Error("invalid heartbeat is added")
+	}
+}
+
+func TestHeartbeatLogsFinalName(t *testing.T) {
+	logs := useRecordLogger(t)
+	ts := newTestScheduler()
+	var names []string
+	for i := 0; i < 2; i++ {
+		name, err := ts.Heartbeat("beacon", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if names[0] == names[1] {
+		t.Fatalf("both heartbeats are named %q", names[0])
+	}
+
+	second := names[1]
+	makeDue(t, ts, second)
+	ts.RunPending()
+	kv, ok := logs.fields("heartbeat: " + second)
+	if !ok {
+		t.Fatalf("heartbeat of %q is not logged with its final name", second)
+	}
+	info, _ := ts.Info(second)
+	if !hasField(kv, "task_id", info.ID) {
+		t.Errorf("heartbeat logged with %v, want the task ID %s", kv, info.ID)
+	}
+	if n := logs.count("heartbeat: "); n != 1 {
+		t.Errorf("%d heartbeats logged, want only the due one", n)
+	}
+}
@@ -0,0 +1,302 @@
+package isked
+
+import (
+	"sort"
+	"time"
+)
+
+// TaskStats is the execution statistics of a task
+type TaskStats struct {
+	Runs          int           // number of completed runs
+	Errors        int           // number of runs that returned an error
+	LastDuration  time.Duration // duration of the last completed run
+	TotalDuration time.Duration // total duration of all the completed runs
+}
+
+// AvgDuration returns the average duration of the completed runs
+func (st TaskStats) AvgDuration() time.Duration {
+	if st.Runs == 0 {
+		return 0
+	}
+	return st.TotalDuration / time.Duration(st.Runs)
+}
+
+// TaskInfo is the public information of a task
+type TaskInfo struct {
+	ID                string // immutable task ID, it doesn't change when the task is rescheduled
+	Name              string
+	RunType           RunType
+	FrequencyInterval string         // frequently option only: seconds, minutes, hours
+	FrequencyValue    int            // frequently option only
+	Interval          time.Duration  // frequently option only, the interval as duration
+	At                string         // 24-hour clock, e.g "15:04", empty if not set
+	Weekday           time.Weekday   // weekly option only
+	WeekOfMonth       int            // weekly option only, 0 for every week
+	MonthDay          int            // monthly option only
+	Location          *time.Location // timezone of the 'At' time
+	Limit             int            // maximum number of runs, 0 means no limit
+	StartingFrom      time.Time      // zero if not set
+	Until             time.Time      // end of the date range, zero if not set
+	UntilSuccess      bool
+	Paused            bool
+	Backfill          int         // maximum number of missed runs to catch up on
+	Dates             []time.Time // onetime option only, the DateTime to run on after the next run
+	NextRun           time.Time   // zero if there's no next run
+	LastRun           time.Time   // zero if it never ran
+	Created           time.Time
+	Stats             TaskStats
+	LastError         error     // error of the last run, nil if it succeeded or never ran
+	LastErrorAt       time.Time // zero if there's no last error
+}
+
+// Info gets the public information of the task using the task name or the task ID
+func (t *TaskScheduler) Info(taskName string) (TaskInfo, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		return TaskInfo{}, false
+	}
+	return taskData[0].info(), true
+}
+
+// LastError gets the error of the last run of the task using the task name or the task ID and the time
+// it happened, the error is nil if the last run succeeded.
+func (t *TaskScheduler) LastError(taskName string) (error, time.Time, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		return nil, time.Time{}, false
+	}
+	return taskData[0].lastErr, taskData[0].lastErrAt, true
+}
+
+// Remaining gets the number of runs left of the task using the task name or the task ID, -1 if
+// the runs are not bounded, e.g a daily task without a limit.
+func (t *TaskScheduler) Remaining(taskName string) (int, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		return 0, false
+	}
+	return taskData[0].remaining(), true
+}
+
+// remaining returns the number of runs left of the task, -1 if it's not bounded
+func (s *Tasks) remaining() int {
+	left := -1
+	switch {
+	case s.onDates:
+		left = len(s.dates) + 1 // The dates after the next run
+	case s.RunType == _onetime:
+		left = 0
+		if !s.nextRunTime.IsZero() {
+			left = 1
+		}
+	default:
+		left = s.slotsUntil()
+	}
+	if s.limit > 0 && (left < 0 || s.limit-s.runCount < left) {
+		left = s.limit - s.runCount
+	}
+	return left
+}
+
+// maxSlots is the most runs counted one by one until the end of the date range
+const maxSlots = 100000
+
+// slotsUntil returns the number of runs of the recurring task from its next run until the end of its
+// date range, -1 if it has no end or its runs can't be known ahead, e.g with the 'NextFunc' method
+func (s *Tasks) slotsUntil() int {
+	if s.until.IsZero() || s.nextRunTime.IsZero() || s.nextFunc != nil || s.nextAt != nil {
+		return -1
+	}
+	next, count := s.nextRunTime, 0
+	if s.firstRunAt.After(next) {
+		next, count = s.firstRunAt, 1 // The immediate run, then back to the schedule
+	}
+	if next.After(s.until) {
+		return count
+	}
+
+	switch {
+	case s.RunType == _frequently:
+		interval := s.interval()
+		if interval <= 0 {
+			return -1
+		}
+		return count + 1 + int(s.until.Sub(next)/interval)
+
+	case s.RunType == _monthly && s.strictMonthDay && s.requestedDay > 0:
+		// The months without the day are skipped
+		loc := s.location()
+		next = next.In(loc)
+		for month := time.Date(next.Year(), next.Month(), 1, 0, 0, 0, 0, loc); ; month = month.AddDate(0, 1, 0) {
+			at := time.Date(month.Year(), month.Month(), s.requestedDay, next.Hour(), next.Minute(), 0, 0, loc)
+			if daysIn(month) < s.requestedDay || at.Before(next) {
+				continue
+			}
+			if at.After(s.until) {
+				return count
+			}
+			count++
+		}
+	}
+
+	for ; !next.IsZero() && !next.After(s.until); next = s.nextSchedule(next) {
+		if count == maxSlots {
+			return -1
+		}
+		count++
+	}
+	return count
+}
+
+// ForEach calls the fn for each task in no particular order while holding the lock of the task list,
+// it stops early if fn returns false. The fn must not call any method of the same task scheduler
+// since the lock is not re-entrant.
+func (t *TaskScheduler) ForEach(fn func(info TaskInfo) bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, e := range t.TaskList {
+		for i := range e {
+			if !fn(e[i].info()) {
+				return
+			}
+		}
+	}
+}
+
+// ListByRunType gets the public information of the tasks with the given run type sorted by the task name
+func (t *TaskScheduler) ListByRunType(rt RunType) []TaskInfo {
+	t.mu.RLock()
+	var list []TaskInfo
+	for _, e := range t.TaskList {
+		for i := range e {
+			if RunType(e[i].RunType) == rt {
+				list = append(list, e[i].info())
+			}
+		}
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// DueWithin gets the names of the tasks sorted by name whose next run is within the duration from now,
+// including the overdue ones but not the paused ones, e.g to prefetch the resources before a batch of tasks run.
+func (t *TaskScheduler) DueWithin(d time.Duration) []string {
+	until := time.Now().Add(d)
+	t.mu.RLock()
+	var names []string
+	for _, e := range t.TaskList {
+		for i := range e {
+			if !e[i].nextRunTime.IsZero() && !e[i].nextRunTime.After(until) && !e[i].paused {
+				names = append(names, e[i].Name)
+			}
+		}
+	}
+	t.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// ExpectedRunsPerDay estimates the number of runs per day of each task from its schedule, e.g 288 for the
+// frequently option every 5 minutes, 1/7 for the weekly option and 12/365.25 for the monthly option. The onetime
+// tasks count their runs within the next 24 hours, the paused tasks count as 0 and the tasks with a custom
+// schedule from the 'NextFunc' method are not included.
+func (t *TaskScheduler) ExpectedRunsPerDay() map[string]float64 {
+	now := time.Now()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	runs := make(map[string]float64, len(t.TaskList))
+	for _, e := range t.TaskList {
+		for i := range e {
+			if e[i].nextFunc != nil {
+				continue
+			}
+			runs[e[i].Name] = e[i].runsPerDay(now)
+		}
+	}
+	return runs
+}
+
+// runsPerDay estimates the number of runs per day of the task
+func (s *Tasks) runsPerDay(now time.Time) float64 {
+	if s.paused {
+		return 0
+	}
+	switch s.RunType {
+	case _frequently:
+		if s.interval() <= 0 {
+			return 0
+		}
+		return float64(24*time.Hour) / float64(s.interval())
+	case _daily:
+		return 1
+	case _weekly:
+		return 1.0 / 7
+	case _monthly:
+		return 12 / 365.25
+	case _onetime:
+		until := now.Add(24 * time.Hour)
+		n := 0
+		for _, dt := range append([]time.Time{s.nextRunTime}, s.dates...) {
+			if !dt.IsZero() && !dt.After(until) {
+				n++
+			}
+		}
+		return float64(n)
+	}
+	return 0
+}
+
+// info converts the task to its public information
+func (s *Tasks) info() TaskInfo {
+	ti := TaskInfo{
+		ID:                s.id,
+		Name:              s.Name,
+		RunType:           RunType(s.RunType),
+		FrequencyInterval: s.FrequencyInterval,
+		FrequencyValue:    s.FrequencyValue,
+		Interval:          s.interval(),
+		Weekday:           s.dayName,
+		WeekOfMonth:       s.weekOfMonth,
+		MonthDay:          s.runDay(),
+		Location:          s.location(),
+		Limit:             s.limit,
+		UntilSuccess:      s.untilSuccess,
+		Paused:            s.paused,
+		Backfill:          s.backfill,
+		Dates:             append([]time.Time(nil), s.dates...),
+		NextRun:           s.nextRunTime,
+		LastRun:           s.lastRunTime,
+		Created:           s.created,
+		StartingFrom:      s.startingFrom,
+		Until:             s.until,
+		Stats:             s.stats,
+		LastError:         s.lastErr,
+		LastErrorAt:       s.lastErrAt,
+	}
+	if s.isRunAt {
+		ti.At = s.runAtHour + ":" + s.runAtMinute
+	}
+	return ti
+}
+
+// interval returns the frequently interval as duration
+func (s *Tasks) interval() time.Duration {
+	switch s.FrequencyInterval {
+	case _seconds:
+		return time.Second * time.Duration(s.FrequencyValue)
+	case _minutes:
+		return time.Minute * time.Duration(s.FrequencyValue)
+	case _hours:
+		return time.Hour * time.Duration(s.FrequencyValue)
+	}
+	return 0
+}
@@ -0,0 +1,322 @@
+package isked
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRemainingLimit(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("limited").Frequently().Minutes(1).Limit(5).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if left, _ := ts.Remaining("limited"); left != 5 {
+		t.Errorf("%d runs left before running, want 5", left)
+	}
+	for i := 0; i < 2; i++ {
+		makeDue(t, ts, "limited")
+		ts.RunPending()
+	}
+	info, _ := ts.Info("limited")
+	if left, ok := ts.Remaining(info.ID); !ok || left != 3 {
+		t.Errorf("%d runs left after 2 runs, want 3", left)
+	}
+
+	if _, err := ts.addTask(newTestTask("open").Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if left, _ := ts.Remaining("open"); left != -1 {
+		t.Errorf("%d runs left of an unbounded task, want -1", left)
+	}
+	if _, ok := ts.Remaining("missing"); ok {
+		t.Error("missing task has runs left")
+	}
+}
+
+func TestRemainingBetween(t *testing.T) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 3).Add(-time.Second)
+
+	ts := newTestScheduler()
+	tasks := map[string]*Tasks{
+		"daily":   newTestTask("daily").Daily().At("10:00").Between(start, end).ExecFunc(func() {}),
+		"limited": newTestTask("limited").Daily().At("10:00").Between(start, end).Limit(2).ExecFunc(func() {}),
+		"weekly":  newTestTask("weekly").Weekly().Monday().At("10:00").Between(start, start.AddDate(0, 0, 21)).ExecFunc(func() {}),
+		"open":    newTestTask("open").Daily().At("10:00").ExecFunc(func() {}),
+	}
+	want := map[string]int{"daily": 3, "limited": 2, "weekly": 3, "open": -1}
+	for name, s := range tasks {
+		if _, err := ts.addTask(s); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := ts.Remaining(name); got != want[name] {
+			t.Errorf("%s: Remaining = %d, want %d", name, got, want[name])
+		}
+	}
+}
+
+func TestSlotsUntil(t *testing.T) {
+	next := time.Date(2031, time.January, 1, 10, 0, 0, 0, time.Local)
+	frequently := newTestTask("frequently").Frequently().Hours(1)
+	frequently.nextRunTime, frequently.until = next, next.Add(5*time.Hour)
+	if got := frequently.slotsUntil(); got != 6 {
+		t.Errorf("frequently: %d slots, want 6", got)
+	}
+
+	startup := newTestTask("startup").Frequently().Hours(1)
+	startup.nextRunTime, startup.firstRunAt, startup.until = next.Add(-time.Hour), next, next.Add(5*time.Hour)
+	if got := startup.slotsUntil(); got != 7 {
+		t.Errorf("startup: %d slots, want 7 with the immediate run", got)
+	}
+
+	// Only the months with 31 days
+	strict := newTestTask("strict").Monthly().Every(31).At("10:00").StrictMonthDay()
+	strict.nextRunTime = time.Date(2031, time.January, 31, 10, 0, 0, 0, time.Local)
+	strict.until = time.Date(2031, time.December, 31, 23, 0, 0, 0, time.Local)
+	if got := strict.slotsUntil(); got != 7 {
+		t.Errorf("strict monthly: %d slots, want 7", got)
+	}
+
+	ended := newTestTask("ended").Frequently().Hours(1)
+	ended.nextRunTime, ended.until = next, next.Add(-time.Second)
+	if got := ended.slotsUntil(); got != 0 {
+		t.Errorf("ended: %d slots, want 0", got)
+	}
+}
+
+func TestInfo(t *testing.T) {
+	ts := newTestScheduler()
+	start := time.Now().Add(time.Hour)
+	end := start.AddDate(0, 1, 0)
+	s := newTestTask("weekly-report").Weekly().Wednesday().At("10:30").In(time.UTC).Limit(4).Between(start, end).
+		ExecFuncErr(func() error { return errors.New("report failed") })
+	if _, err := ts.addTask(s); err != nil {
+		t.Fatal(err)
+	}
+
+	info, ok := ts.Info("weekly-report")
+	if !ok {
+		t.Fatal("task not found")
+	}
+	if len(info.ID) == 0 || info.Name != "weekly-report" || info.RunType != RunWeekly {
+		t.Errorf("identity %q %q %q", info.ID, info.Name, info.RunType)
+	}
+	if info.At != "10:30" || info.Weekday != time.Wednesday || info.Location != time.UTC {
+		t.Errorf("schedule %s on %v in %v, want 10:30 on Wednesday in UTC", info.At, info.Weekday, info.Location)
+	}
+	if info.Limit != 4 || !info.StartingFrom.Equal(start) || !info.Until.Equal(end) {
+		t.Errorf("limit %d from %v until %v", info.Limit, info.StartingFrom, info.Until)
+	}
+	if at := info.NextRun.In(time.UTC); at.Weekday() != time.Wednesday || at.Hour() != 10 || at.Minute() != 30 {
+		t.Errorf("next run %v, want Wednesday 10:30 UTC", at)
+	}
+	if !info.LastRun.IsZero() || info.Created.IsZero() || info.Paused {
+		t.Errorf("last run %v, created %v, paused %v", info.LastRun, info.Created, info.Paused)
+	}
+	if byID, ok := ts.Info(info.ID); !ok || byID.Name != info.Name {
+		t.Error("not found by its ID")
+	}
+
+	makeDue(t, ts, "weekly-report")
+	ts.RunPending()
+	if err := ts.Pause("weekly-report"); err != nil {
+		t.Fatal(err)
+	}
+	info, _ = ts.Info("weekly-report")
+	if info.Stats.Runs != 1 || info.Stats.Errors != 1 || info.LastRun.IsZero() {
+		t.Errorf("stats %+v with the last run %v after a failed run", info.Stats, info.LastRun)
+	}
+	if info.LastError == nil || info.LastErrorAt.IsZero() || !info.Paused {
+		t.Errorf("last error %v at %v, paused %v", info.LastError, info.LastErrorAt, info.Paused)
+	}
+	if _, ok := ts.Info("missing"); ok {
+		t.Error("missing task is found")
+	}
+}
+
+func TestInfoFrequently(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("poll").Frequently().Minutes(15).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := ts.Info("poll")
+	if info.FrequencyInterval != _minutes || info.FrequencyValue != 15 || info.Interval != 15*time.Minute {
+		t.Errorf("interval %d %s (%v), want 15 minutes", info.FrequencyValue, info.FrequencyInterval, info.Interval)
+	}
+	if len(info.At) > 0 || info.Location != time.Local {
+		t.Errorf("at %q in %v, want no 'At' time in the local time", info.At, info.Location)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	ts := newTestScheduler()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		if _, err := ts.addTask(newTestTask(name).Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	ts.ForEach(func(info TaskInfo) bool {
+		seen[info.Name] = true
+		return true
+	})
+	if len(seen) != 4 {
+		t.Errorf("visited %d tasks, want 4", len(seen))
+	}
+
+	visited := 0
+	ts.ForEach(func(info TaskInfo) bool {
+		visited++
+		return visited < 2
+	})
+	if visited != 2 {
+		t.Errorf("visited %d tasks, want to stop after 2", visited)
+	}
+}
+
+func TestListByRunType(t *testing.T) {
+	ts := newTestScheduler()
+	tasks := []*Tasks{
+		newTestTask("poll-z").Frequently().Minutes(1),
+		newTestTask("report").Daily().At("09:00"),
+		newTestTask("poll-a").Frequently().Seconds(30),
+		newTestTask("invoice").Monthly().Every(1).At("08:00"),
+		newTestTask("backup").Daily().At("01:00"),
+	}
+	for _, s := range tasks {
+		if _, err := ts.addTask(s.ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for rt, want := range map[RunType][]string{
+		RunFrequently: {"poll-a", "poll-z"},
+		RunDaily:      {"backup", "report"},
+		RunMonthly:    {"invoice"},
+		RunWeekly:     nil,
+	} {
+		list := ts.ListByRunType(rt)
+		var names []string
+		for _, info := range list {
+			if info.RunType != rt {
+				t.Errorf("%s: %s is a %s task", rt, info.Name, info.RunType)
+			}
+			names = append(names, info.Name)
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("%s: %v, want %v sorted by name", rt, names, want)
+		}
+	}
+}
+
+func TestLastError(t *testing.T) {
+	ts := newTestScheduler()
+	errTimeout := errors.New("timeout")
+	var result error
+	if _, err := ts.addTask(newTestTask("fetch").Frequently().Minutes(1).ExecFuncErr(func() error { return result })); err != nil {
+		t.Fatal(err)
+	}
+	if err, at, ok := ts.LastError("fetch"); !ok || err != nil || !at.IsZero() {
+		t.Fatalf("last error %v at %v before any run, want none", err, at)
+	}
+
+	result = errTimeout
+	before := time.Now()
+	makeDue(t, ts, "fetch")
+	ts.RunPending()
+	err, at, _ := ts.LastError("fetch")
+	if !errors.Is(err, errTimeout) || at.Before(before) || at.After(time.Now()) {
+		t.Errorf("last error %v at %v, want the timeout of the run", err, at)
+	}
+	if info, _ := ts.Info("fetch"); !errors.Is(info.LastError, errTimeout) || !info.LastErrorAt.Equal(at) {
+		t.Errorf("info last error %v at %v, want %v at %v", info.LastError, info.LastErrorAt, err, at)
+	}
+
+	result = nil
+	makeDue(t, ts, "fetch")
+	ts.RunPending()
+	if err, at, _ := ts.LastError("fetch"); err != nil || !at.IsZero() {
+		t.Errorf("last error %v at %v after a success, want it cleared", err, at)
+	}
+	if _, _, ok := ts.LastError("missing"); ok {
+		t.Error("missing task has a last error")
+	}
+}
+
+func TestDueWithin(t *testing.T) {
+	ts := newTestScheduler()
+	now := time.Now()
+	for name, next := range map[string]time.Duration{
+		"overdue": -time.Minute,
+		"soon":    10 * time.Second,
+		"edge":    29 * time.Second,
+		"later":   5 * time.Minute,
+		"paused":  5 * time.Second,
+	} {
+		if _, err := ts.addTask(newTestTask(name).Frequently().Hours(1).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+		setNextRun(t, ts, name, now.Add(next))
+	}
+	if err := ts.Pause("paused"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := ts.DueWithin(30*time.Second), []string{"edge", "overdue", "soon"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("due within 30s %v, want %v", got, want)
+	}
+	if got, want := ts.DueWithin(0), []string{"overdue"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("due now %v, want %v", got, want)
+	}
+	if got := newTestScheduler().DueWithin(time.Hour); len(got) != 0 {
+		t.Errorf("empty scheduler has %v due", got)
+	}
+}
+
+func TestExpectedRunsPerDay(t *testing.T) {
+	ts := newTestScheduler()
+	now := time.Now()
+	tasks := []*Tasks{
+		newTestTask("every-5m").Frequently().Minutes(5),
+		newTestTask("every-2h").Frequently().Hours(2),
+		newTestTask("daily").Daily().At("10:00"),
+		newTestTask("weekly").Weekly().Monday().At("09:00"),
+		newTestTask("monthly").Monthly().Every(1).At("10:00"),
+		newTestTask("onetime").OneTime(now.Add(2 * time.Hour).Unix()),
+		newTestTask("dates").OnDates(now.Add(time.Hour), now.Add(2*time.Hour), now.Add(48*time.Hour)),
+		newTestTask("paused").Frequently().Minutes(1),
+		newTestTask("custom").NextFunc(func() time.Duration { return time.Hour }),
+	}
+	for _, s := range tasks {
+		if _, err := ts.addTask(s.ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ts.Pause("paused"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]float64{
+		"every-5m": 288,
+		"every-2h": 12,
+		"daily":    1,
+		"weekly":   1.0 / 7,
+		"monthly":  12 / 365.25,
+		"onetime":  1,
+		"dates":    2,
+		"paused":   0,
+	}
+	got := ts.ExpectedRunsPerDay()
+	if len(got) != len(want) {
+		t.Errorf("estimates for %d tasks, want %d without the custom schedule", len(got), len(want))
+	}
+	for name, runs := range want {
+		if v, ok := got[name]; !ok || v < runs-1e-9 || v > runs+1e-9 {
+			t.Errorf("%s: %v runs per day, want %v", name, v, runs)
+		}
+	}
+}
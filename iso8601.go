@@ -0,0 +1,111 @@
+package isked
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ISO8601 method sets the task as frequently from an ISO 8601 repeating interval, e.g "R/PT15M" or
+// "R5/2024-01-01T00:00:00Z/P1D". The repeat count is used as the 'Limit' and the start DateTime
+// as the 'StartingFrom' value. A start in the past continues on the next repetition with the remaining count,
+// it's an error if all of them already passed. Durations with years or months and the end DateTime forms are not supported.
+func (s *Tasks) ISO8601(expr string) *Tasks {
+	parts := strings.Split(strings.TrimSpace(expr), "/")
+	if len(parts) < 2 || len(parts) > 3 || !strings.HasPrefix(parts[0], "R") {
+		s.setErr("ISO8601", fmt.Errorf("unsupported ISO 8601 repeating interval %q", expr))
+		return s
+	}
+
+	// Repeat count, 'R' alone means unlimited
+	runs := 0
+	if len(parts[0]) > 1 {
+		n, err := strconv.Atoi(parts[0][1:])
+		if err != nil || n <= 0 {
+			s.setErr("ISO8601", fmt.Errorf("invalid ISO 8601 repeat count %q", parts[0]))
+			return s
+		}
+		runs = n
+	}
+
+	d, err := parseISO8601Duration(parts[len(parts)-1])
+	if err != nil {
+		s.setErr("ISO8601", err)
+		return s
+	}
+
+	if len(parts) == 3 {
+		start, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			s.setErr("ISO8601", fmt.Errorf("unsupported ISO 8601 start DateTime %q", parts[1]))
+			return s
+		}
+		// A start in the past skips the repetitions that already passed, the next one keeps the alignment
+		if now := time.Now(); start.Before(now) {
+			passed := int((now.Sub(start) + d - 1) / d)
+			if runs > 0 && passed >= runs {
+				s.setErr("ISO8601", fmt.Errorf("ISO 8601 repeating interval %q already ended", expr))
+				return s
+			}
+			if runs > 0 {
+				runs -= passed
+			}
+			start = start.Add(time.Duration(passed) * d)
+		}
+		s.StartingFrom(start)
+	}
+	s.Frequently().setInterval(d).Limit(runs)
+	return s
+}
+
+// parseISO8601Duration parses the fixed length ISO 8601 durations, e.g "P1DT12H" or "PT15M"
+func parseISO8601Duration(v string) (time.Duration, error) {
+	if len(v) < 3 || v[0] != 'P' {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", v)
+	}
+
+	var total time.Duration
+	inTime := false
+	num := ""
+	for _, c := range v[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T' && !inTime && len(num) == 0:
+			inTime = true
+			continue
+		}
+
+		if len(num) == 0 {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", v)
+		}
+		n, _ := strconv.Atoi(num)
+		num = ""
+
+		var unit time.Duration
+		switch {
+		case !inTime && c == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && c == 'D':
+			unit = 24 * time.Hour
+		case inTime && c == 'H':
+			unit = time.Hour
+		case inTime && c == 'M':
+			unit = time.Minute
+		case inTime && c == 'S':
+			unit = time.Second
+		case !inTime && (c == 'Y' || c == 'M'):
+			return 0, fmt.Errorf("unsupported ISO 8601 duration %q, years and months have no fixed length", v)
+		default:
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", v)
+		}
+		total += time.Duration(n) * unit
+	}
+
+	if len(num) > 0 || total < time.Second {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", v)
+	}
+	return total, nil
+}
@@ -0,0 +1,69 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseISO8601Duration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"PT15M", 15 * time.Minute, true},
+		{"P1DT12H", 36 * time.Hour, true},
+		{"P1W", 7 * 24 * time.Hour, true},
+		{"PT1H30M5S", time.Hour + 30*time.Minute + 5*time.Second, true},
+		{"P1M", 0, false},
+		{"P1Y", 0, false},
+		{"PT", 0, false},
+		{"PT0S", 0, false},
+		{"15M", 0, false},
+		{"PT5", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseISO8601Duration(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseISO8601Duration(%q) = %v, %v, want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestISO8601(t *testing.T) {
+	s := newTestTask("iso").ISO8601("R5/PT15M").ExecFunc(func() {})
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.interval() != 15*time.Minute || s.limit != 5 {
+		t.Fatalf("interval %v, limit %d, want 15m and 5", s.interval(), s.limit)
+	}
+
+	for _, expr := range []string{"PT15M", "R0/PT15M", "Rx/PT15M", "R/bad/PT1H", "R/P1M"} {
+		if err := newTestTask("iso").ISO8601(expr).ExecFunc(func() {}).Validate(); err == nil {
+			t.Errorf("ISO8601(%q) is accepted", expr)
+		}
+	}
+}
+
+func TestISO8601PastStart(t *testing.T) {
+	if err := newTestTask("ended").ISO8601("R5/2024-01-01T00:00:00Z/P1D").ExecFunc(func() {}).Validate(); err == nil {
+		t.Fatal("an ISO 8601 repeating interval that already ended is accepted")
+	}
+
+	// Every hour since the start, the next run stays on the hour and the passed repetitions are not counted
+	start := time.Now().Truncate(time.Hour).Add(-5 * time.Hour)
+	ts := newTestScheduler()
+	s := newTestTask("aligned").ISO8601("R10/" + start.UTC().Format(time.RFC3339) + "/PT1H").ExecFunc(func() {})
+	first, err := ts.addTask(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := start.Add(6 * time.Hour)
+	if !first.Equal(want) {
+		t.Errorf("first run %v, want %v", first, want)
+	}
+	if left, _ := ts.Remaining("aligned"); left != 4 {
+		t.Errorf("Remaining = %d, want 4", left)
+	}
+}
@@ -0,0 +1,76 @@
+package isked
+
+import (
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// SetDefaultLocation sets the timezone of the tasks that don't use the 'In' method, nil means the local
+// time. It applies to the tasks added from now on and to the existing ones, with recompute the next runs
+// of the existing daily, weekly, monthly and phased frequently tasks are also moved to the new timezone,
+// otherwise they keep their next run and only the runs after it use the new timezone.
+func (t *TaskScheduler) SetDefaultLocation(loc *time.Location, recompute bool) {
+	t.mu.Lock()
+	t.defaultLoc = loc
+	var affected []Tasks
+	for _, e := range t.TaskList {
+		if len(e) == 0 || e[0].loc != nil {
+			continue
+		}
+		e[0].defaultLoc = loc
+		if recompute && e[0].dependsOnLocation() {
+			affected = append(affected, e[0])
+		}
+	}
+	t.mu.Unlock()
+
+	// Compute the next runs outside the lock, then keep the ones that are not changed in the meantime
+	now := time.Now()
+	for _, s := range affected {
+		nextSchedToRun := s.nextSchedule(now)
+		if s.startingFrom.After(now) {
+			nextSchedToRun = s.firstRunFrom(s.startingFrom)
+		}
+		if nextSchedToRun.IsZero() {
+			continue
+		}
+
+		t.mu.Lock()
+		cur, ok := t.TaskList[s.Name]
+		if !ok || len(cur) == 0 || !cur[0].nextRunTime.Equal(s.nextRunTime) || cur[0].loc != nil {
+			t.mu.Unlock()
+			continue
+		}
+		cur[0].nextRunTime = nextSchedToRun
+		t.emit(ChangeRescheduled, &cur[0])
+		t.mu.Unlock()
+
+		nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
+		msg := s.Name + " is moved to the timezone " + s.location().String() + ", next schedule to run on: " + nextSched
+		logger().Infow(msg, s.logKV()...)
+		color.Magenta(msg)
+	}
+	t.notify()
+}
+
+// defaultLocation returns the default timezone of the task scheduler, nil for the local time
+func (t *TaskScheduler) defaultLocation() *time.Location {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.defaultLoc
+}
+
+// dependsOnLocation checks if the next run of the task is computed from the time of day in its timezone
+func (s *Tasks) dependsOnLocation() bool {
+	if s.nextFunc != nil || s.onDates || s.nextRunTime.IsZero() {
+		return false
+	}
+	switch s.RunType {
+	case _daily, _weekly, _monthly:
+		return true
+	case _frequently:
+		return s.hasPhase && !s.epochAligned
+	}
+	return false
+}
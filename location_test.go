@@ -0,0 +1,97 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skip(err)
+	}
+	return loc
+}
+
+func TestSetDefaultLocationRecompute(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	newYork := loadLocation(t, "America/New_York")
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("unpinned").Daily().At("09:00").ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.addTask(newTestTask("pinned").Daily().At("09:00").In(newYork).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	pinnedBefore, _ := ts.Info("pinned")
+
+	ts.SetDefaultLocation(tokyo, true)
+	unpinned, _ := ts.Info("unpinned")
+	if at := unpinned.NextRun.In(tokyo); at.Hour() != 9 || at.Minute() != 0 {
+		t.Errorf("unpinned next run %v, want 09:00 in Tokyo", at)
+	}
+	if unpinned.Location != tokyo {
+		t.Errorf("unpinned location %v, want Asia/Tokyo", unpinned.Location)
+	}
+	pinned, _ := ts.Info("pinned")
+	if !pinned.NextRun.Equal(pinnedBefore.NextRun) || pinned.Location != newYork {
+		t.Errorf("pinned task changed to %v in %v", pinned.NextRun, pinned.Location)
+	}
+	if got := ts.Config().DefaultLocation; got != tokyo {
+		t.Errorf("Config().DefaultLocation = %v", got)
+	}
+}
+
+func TestSetDefaultLocationWithoutRecompute(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("kept").Daily().At("09:00").ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := ts.Info("kept")
+	ts.SetDefaultLocation(tokyo, false)
+	after, _ := ts.Info("kept")
+	if !after.NextRun.Equal(before.NextRun) {
+		t.Errorf("next run moved from %v to %v without recompute", before.NextRun, after.NextRun)
+	}
+	if after.Location != tokyo {
+		t.Errorf("location %v, want Asia/Tokyo for the runs after the next one", after.Location)
+	}
+}
+
+func TestDefaultLocationOfEachAddPath(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	ts := newTestScheduler()
+	ts.SetDefaultLocation(tokyo, false)
+
+	if errs := ts.AddBatchAt(time.Now().Add(time.Hour), newTestTask("batch").Daily().At("09:00").ExecFunc(func() {})); errs != nil {
+		t.Fatal(errs)
+	}
+	if err := ts.Import(TaskDef{Name: "imported", RunType: RunDaily, At: "09:00"}, func() {}); err != nil {
+		t.Fatal(err)
+	}
+	defs := []TaskDef{
+		{Name: "batch", RunType: RunDaily, At: "09:00"},
+		{Name: "imported", RunType: RunDaily, At: "09:00"},
+		{Name: "reloaded", RunType: RunDaily, At: "09:00"},
+	}
+	registry := map[string]FuncToExec{"batch": func() {}, "imported": func() {}, "reloaded": func() {}}
+	if err := ts.ReloadFrom(defs, registry); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"batch", "imported", "reloaded"} {
+		info, ok := ts.Info(name)
+		if !ok {
+			t.Fatalf("%s is not found", name)
+		}
+		if info.Location != tokyo {
+			t.Errorf("%s location %v, want Asia/Tokyo", name, info.Location)
+		}
+	}
+	reloaded, _ := ts.Info("reloaded")
+	if at := reloaded.NextRun.In(tokyo); at.Hour() != 9 || at.Minute() != 0 {
+		t.Errorf("reloaded next run %v, want 09:00 in Tokyo", at)
+	}
+}
@@ -0,0 +1,66 @@
+package isked
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/itrepablik/itrlog"
+)
+
+// Logger is the structured logger of the task schedulers, e.g a '*zap.SugaredLogger'.
+// Default logs to the 'itrlog' package.
+type Logger interface {
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+var (
+	activeLogger Logger = itrLogger{}
+	loggerMu     sync.RWMutex
+)
+
+// SetLogger replaces the logger of all the task schedulers, a nil logger including a typed nil
+// pointer turns off the logs instead of panicking on each scheduling call.
+func SetLogger(l Logger) {
+	if isNilLogger(l) {
+		l = nopLogger{}
+	}
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	activeLogger = l
+}
+
+// logger returns the logger in use
+func logger() Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return activeLogger
+}
+
+// isNilLogger checks if the logger is nil or holds a nil value, e.g '(*zap.SugaredLogger)(nil)'
+func isNilLogger(l Logger) bool {
+	if l == nil {
+		return true
+	}
+	v := reflect.ValueOf(l)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// itrLogger is the default logger using the 'itrlog' package
+type itrLogger struct{}
+
+func (itrLogger) Infow(msg string, kv ...interface{})  { itrlog.Infow(msg, kv...) }
+func (itrLogger) Warnw(msg string, kv ...interface{})  { itrlog.Warnw(msg, kv...) }
+func (itrLogger) Errorw(msg string, kv ...interface{}) { itrlog.Errorw(msg, kv...) }
+
+// nopLogger discards all the logs
+type nopLogger struct{}
+
+func (nopLogger) Infow(msg string, kv ...interface{})  {}
+func (nopLogger) Warnw(msg string, kv ...interface{})  {}
+func (nopLogger) Errorw(msg string, kv ...interface{}) {}
@@ -0,0 +1,75 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+)
+
+// hasField checks if the key-value pairs of a log contain the key with the value
+func hasField(kv []interface{}, key string, value interface{}) bool {
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i] == key && kv[i+1] == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLogFields(t *testing.T) {
+	logs := useRecordLogger(t)
+	ts := newTestScheduler()
+	s := newTestTask("tenant-sync").Frequently().Minutes(1).LogFields("tenant", "acme", "env", "prod").
+		ExecFuncErr(func() error { return errors.New("sync failed") })
+	if _, err := ts.addTask(s); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "tenant-sync")
+	ts.RunPending()
+
+	for _, msg := range []string{
+		"tenant-sync base start datetime at",
+		"tenant-sync next schedule to run on",
+		"tenant-sync returns an error",
+	} {
+		kv, ok := logs.fields(msg)
+		if !ok {
+			t.Errorf("%q is not logged", msg)
+			continue
+		}
+		if !hasField(kv, "tenant", "acme") || !hasField(kv, "env", "prod") || kv[0] != "log_time" {
+			t.Errorf("%q is logged with %v, want the log time and the task's fields", msg, kv)
+		}
+	}
+}
+
+func TestNilLogger(t *testing.T) {
+	t.Cleanup(func() { SetLogger(nil) })
+	var typedNil *recordLogger
+	for _, l := range []Logger{nil, typedNil} {
+		SetLogger(l)
+		if _, ok := logger().(nopLogger); !ok {
+			t.Fatalf("logger %T is used for %#v, want the no-op logger", logger(), l)
+		}
+
+		ts := newTestScheduler()
+		runs := 0
+		if _, err := ts.addTask(newTestTask("ok").Frequently().Minutes(1).ExecFunc(func() { runs++ })); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ts.addTask(newTestTask("failing").Frequently().Minutes(1).ExecFuncErr(func() error {
+			runs++
+			return errors.New("failed")
+		})); err != nil {
+			t.Fatal(err)
+		}
+		ts.addTask(newTestTask("invalid").Frequently())
+		makeDue(t, ts, "ok")
+		makeDue(t, ts, "failing")
+		ts.RunPending()
+		ts.RemoveTask("ok")
+		ts.Close()
+		if runs != 2 {
+			t.Errorf("%d runs with the %T logger, want 2", runs, l)
+		}
+	}
+}
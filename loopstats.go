@@ -0,0 +1,70 @@
+package isked
+
+import "time"
+
+// LoopStats is the timing of the running loop itself, use it to check if the task scheduler
+// is the bottleneck with a lot of tasks. The run time of the tasks is not included.
+type LoopStats struct {
+	Iterations          int           // number of due checks
+	IterationsPerSecond float64       // iterations since the first one
+	AvgIteration        time.Duration // average duration of the due check and dispatch of the due tasks
+	LockHeld            time.Duration // total time holding the lock of the task list during the due checks
+	Started             time.Time     // time of the first iteration, zero if the loop never ran
+	Executions          int64         // completed runs of all the tasks including the namespaces
+
+	total time.Duration
+}
+
+// LoopStats gets the timing of the running loop of the task scheduler
+func (t *TaskScheduler) LoopStats() LoopStats {
+	t.loopMu.Lock()
+	st := t.loop
+	st.Executions = t.executions
+	t.loopMu.Unlock()
+
+	if st.Iterations > 0 {
+		st.AvgIteration = st.total / time.Duration(st.Iterations)
+		if elapsed := time.Since(st.Started).Seconds(); elapsed > 0 {
+			st.IterationsPerSecond = float64(st.Iterations) / elapsed
+		}
+	}
+	return st
+}
+
+// Uptime gets how long the running loop of the task scheduler has been running, zero if it's not running.
+// It starts from zero again when the loop is restarted, e.g after a reload.
+func (t *TaskScheduler) Uptime() time.Duration {
+	t.loopMu.Lock()
+	defer t.loopMu.Unlock()
+	if t.loopStarted.IsZero() {
+		return 0
+	}
+	return time.Since(t.loopStarted)
+}
+
+// root returns the task scheduler that runs the loop of the namespace, itself if it's not a namespace
+func (t *TaskScheduler) root() *TaskScheduler {
+	for t.parent != nil {
+		t = t.parent
+	}
+	return t
+}
+
+// countExecution adds the completed run to the total executions
+func (t *TaskScheduler) countExecution() {
+	t.loopMu.Lock()
+	t.executions++
+	t.loopMu.Unlock()
+}
+
+// recordLoop adds the iteration of the running loop to its timing
+func (t *TaskScheduler) recordLoop(d, lockHeld time.Duration) {
+	t.loopMu.Lock()
+	defer t.loopMu.Unlock()
+	if t.loop.Started.IsZero() {
+		t.loop.Started = time.Now().Add(-d)
+	}
+	t.loop.Iterations++
+	t.loop.total += d
+	t.loop.LockHeld += lockHeld
+}
@@ -0,0 +1,106 @@
+package isked
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the test output readable, the logs are covered by their own tests
+	SetLogger(nil)
+	color.Output = io.Discard
+	os.Exit(m.Run())
+}
+
+// newTestScheduler creates an empty task scheduler that is not shared with the other tests
+func newTestScheduler() *TaskScheduler {
+	return &TaskScheduler{TaskList: make(map[string][]Tasks)}
+}
+
+// newTestTask creates a task that can be added to any task scheduler
+func newTestTask(name string) *Tasks {
+	return &Tasks{
+		Name:      name,
+		monthName: time.Now().Local().Month(),
+		created:   time.Now(),
+	}
+}
+
+// recordLogger keeps the logs with their key-value pairs in the order they are logged
+type recordLogger struct {
+	mu   sync.Mutex
+	logs []recordedLog
+}
+
+// recordedLog is a log kept by the recordLogger
+type recordedLog struct {
+	msg string
+	kv  []interface{}
+}
+
+func (l *recordLogger) Infow(msg string, kv ...interface{})  { l.record(msg, kv) }
+func (l *recordLogger) Warnw(msg string, kv ...interface{})  { l.record(msg, kv) }
+func (l *recordLogger) Errorw(msg string, kv ...interface{}) { l.record(msg, kv) }
+
+func (l *recordLogger) record(msg string, kv []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logs = append(l.logs, recordedLog{msg: msg, kv: kv})
+}
+
+// fields returns the key-value pairs of the first log that contains the text and if it's logged
+func (l *recordLogger) fields(text string) ([]interface{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.logs {
+		if strings.Contains(e.msg, text) {
+			return e.kv, true
+		}
+	}
+	return nil, false
+}
+
+// count returns the number of logs that contain the text
+func (l *recordLogger) count(text string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, e := range l.logs {
+		if strings.Contains(e.msg, text) {
+			n++
+		}
+	}
+	return n
+}
+
+// useRecordLogger replaces the logger until the end of the test
+func useRecordLogger(t *testing.T) *recordLogger {
+	l := &recordLogger{}
+	SetLogger(l)
+	t.Cleanup(func() { SetLogger(nil) })
+	return l
+}
+
+// makeDue moves the next run of the task to the past so the next 'RunPending' runs it
+func makeDue(t *testing.T, ts *TaskScheduler, name string) {
+	t.Helper()
+	setNextRun(t, ts, name, time.Now().Add(-time.Second))
+}
+
+// setNextRun moves the next run of the task to the given DateTime
+func setNextRun(t *testing.T, ts *TaskScheduler, name string, at time.Time) {
+	t.Helper()
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	cur, ok := ts.TaskList[name]
+	if !ok || len(cur) == 0 {
+		t.Fatalf("%s: %v", name, ErrTaskNotFound)
+	}
+	cur[0].nextRunTime = at
+}
@@ -0,0 +1,79 @@
+package isked
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// SetMaintenanceMode turns the maintenance mode on or off, no task runs while it's on but the running
+// loop keeps going and the recurring tasks move on to their next schedule. The onetime tasks that are due
+// are held until the maintenance mode is off.
+func (t *TaskScheduler) SetMaintenanceMode(on bool) {
+	v := int32(0)
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&t.maintenance, v)
+	t.notify()
+
+	msg := "maintenance mode is off"
+	if on {
+		msg = "maintenance mode is on"
+	}
+	logger().Warnw(msg, "log_time", time.Now().Format(logDateTimeFormat))
+	color.Yellow(msg)
+}
+
+// SetMaintenanceCheck sets the fn that is checked on every pass of the running loop, e.g to read a flag
+// from a config service, the maintenance mode is on if fn returns true. Use a nil fn to remove it.
+func (t *TaskScheduler) SetMaintenanceCheck(fn func() bool) {
+	t.mu.Lock()
+	t.maintenanceCheck = fn
+	t.mu.Unlock()
+	t.notify()
+}
+
+// inMaintenance checks if the maintenance mode of the task scheduler is on
+func (t *TaskScheduler) inMaintenance() bool {
+	if atomic.LoadInt32(&t.maintenance) == 1 {
+		return true
+	}
+	t.mu.RLock()
+	fn := t.maintenanceCheck
+	t.mu.RUnlock()
+	return fn != nil && fn()
+}
+
+// skipMaintenance moves the due run of the recurring task to its next schedule without running it
+func (t *TaskScheduler) skipMaintenance(s *Tasks, now time.Time) {
+	if s.RunType == _onetime {
+		return
+	}
+	base := now
+	if s.nextRunTime.After(base) {
+		base = s.nextRunTime
+	}
+	nextSchedToRun := s.nextSchedule(base)
+	if nextSchedToRun.IsZero() {
+		return
+	}
+
+	t.mu.Lock()
+	logNextSched := false
+	if cur, ok := t.TaskList[s.Name]; ok && len(cur) > 0 {
+		logNextSched = t.allowLog(&cur[0])
+		cur[0].nextRunTime = nextSchedToRun
+		t.emit(ChangeRescheduled, &cur[0])
+	}
+	t.mu.Unlock()
+
+	if !logNextSched {
+		return
+	}
+	nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
+	msg := s.Name + " is skipped in maintenance mode, next schedule to run on: " + nextSched
+	logger().Infow(msg, s.logKV()...)
+	color.Magenta(msg)
+}
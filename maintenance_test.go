@@ -0,0 +1,57 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	ts := newTestScheduler()
+	runs := map[string]int{}
+	at := time.Now().Add(time.Hour)
+	for _, s := range []*Tasks{
+		newTestTask("poll").Frequently().Minutes(1),
+		newTestTask("reminder").OneTime(at.Unix()),
+	} {
+		name := s.Name
+		if _, err := ts.addTask(s.ExecFunc(func() { runs[name]++ })); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ts.SetMaintenanceMode(true)
+	makeDue(t, ts, "poll")
+	makeDue(t, ts, "reminder")
+	ts.RunPending()
+	if runs["poll"] != 0 || runs["reminder"] != 0 {
+		t.Fatalf("runs %v while in maintenance, want none", runs)
+	}
+	// The recurring task moves on to its next schedule, the onetime task is held
+	if info, _ := ts.Info("poll"); !info.NextRun.After(time.Now()) {
+		t.Errorf("recurring next run %v, want its next schedule", info.NextRun)
+	}
+	if info, _ := ts.Info("reminder"); info.NextRun.After(time.Now()) {
+		t.Errorf("onetime next run %v, want it held as due", info.NextRun)
+	}
+
+	ts.SetMaintenanceMode(false)
+	ts.RunPending()
+	if runs["reminder"] != 1 || runs["poll"] != 0 {
+		t.Errorf("runs %v after the maintenance, want only the held onetime task", runs)
+	}
+
+	// The same with the external check
+	on := true
+	ts.SetMaintenanceCheck(func() bool { return on })
+	makeDue(t, ts, "poll")
+	ts.RunPending()
+	if runs["poll"] != 0 {
+		t.Errorf("%d runs while the check reports maintenance, want 0", runs["poll"])
+	}
+	on = false
+	makeDue(t, ts, "poll")
+	ts.RunPending()
+	if runs["poll"] != 1 {
+		t.Errorf("%d runs once the check reports no maintenance, want 1", runs["poll"])
+	}
+}
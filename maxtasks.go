@@ -0,0 +1,38 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMaxTasks is returned when a task is added while the task list is already at its maximum number of tasks
+var ErrMaxTasks = errors.New("maximum number of tasks reached")
+
+// SetMaxTasks limits the number of tasks in the task list, e.g when the tasks are registered from
+// the user's input. Adding a task beyond it returns 'ErrMaxTasks'. Default is 0 which means no limit.
+func (t *TaskScheduler) SetMaxTasks(n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	t.maxTasks = n
+}
+
+// checkMaxTasks checks if the new tasks fit in the task list, the tasks that replace an existing one
+// are not counted. The lock of the task list must be held.
+func (t *TaskScheduler) checkMaxTasks(names ...string) error {
+	if t.maxTasks <= 0 {
+		return nil
+	}
+	count := len(t.TaskList)
+	for _, name := range names {
+		if _, ok := t.TaskList[name]; !ok {
+			count++
+		}
+	}
+	if count > t.maxTasks {
+		return fmt.Errorf("%w, the limit is %d", ErrMaxTasks, t.maxTasks)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaxTasks(t *testing.T) {
+	ts := newTestScheduler()
+	ts.SetMaxTasks(2)
+	add := func(name string) error {
+		_, err := ts.addTask(newTestTask(name).Frequently().Minutes(1).ExecFunc(func() {}))
+		return err
+	}
+	for _, name := range []string{"a", "b"} {
+		if err := add(name); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	if err := add("c"); !errors.Is(err, ErrMaxTasks) {
+		t.Errorf("third task error %v, want ErrMaxTasks", err)
+	}
+	if _, ok := ts.Get("c"); ok || len(ts.TaskList) != 2 {
+		t.Errorf("%d tasks, want the cap of 2", len(ts.TaskList))
+	}
+
+	// A name that is already added is reported as such, removing one makes room
+	if err := add("a"); !errors.Is(err, ErrTaskExists) {
+		t.Errorf("adding an existing task at the cap: %v, want ErrTaskExists", err)
+	}
+	ts.RemoveTask("b")
+	if err := add("c"); err != nil {
+		t.Errorf("adding after a removal: %v", err)
+	}
+
+	ts.SetMaxTasks(0)
+	for _, name := range []string{"d", "e", "f"} {
+		if err := add(name); err != nil {
+			t.Errorf("%s without a cap: %v", name, err)
+		}
+	}
+}
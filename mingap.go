@@ -0,0 +1,64 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// MinGap method keeps at least the given duration between the end of a run and the start of the next run,
+// a run that is due sooner, e.g while the previous run is still in progress, is deferred until the gap has passed.
+func (s *Tasks) MinGap(d time.Duration) *Tasks {
+	if d < 0 {
+		s.setErr("MinGap", fmt.Errorf("invalid minimum gap %v", d))
+		return s
+	}
+	s.minGap = d
+	return s
+}
+
+// markRunning counts the run of the task as in progress until its run is recorded
+func (t *TaskScheduler) markRunning(taskName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cur, ok := t.TaskList[taskName]; ok && len(cur) > 0 {
+		cur[0].running++
+	}
+}
+
+// deferMinGap moves the due run of the task until its minimum gap has passed, it returns false if
+// the task can run now.
+func (t *TaskScheduler) deferMinGap(s *Tasks, now time.Time) bool {
+	if s.minGap <= 0 {
+		return false
+	}
+
+	var nextSchedToRun time.Time
+	switch {
+	case s.running > 0:
+		nextSchedToRun = now.Add(s.minGap) // The end is not known yet, check again later
+	case !s.lastRunEnd.IsZero() && now.Sub(s.lastRunEnd) < s.minGap:
+		nextSchedToRun = s.lastRunEnd.Add(s.minGap)
+	default:
+		return false
+	}
+
+	t.mu.Lock()
+	logNextSched := false
+	if cur, ok := t.TaskList[s.Name]; ok && len(cur) > 0 {
+		logNextSched = t.allowLog(&cur[0])
+		cur[0].nextRunTime = nextSchedToRun
+		t.emit(ChangeRescheduled, &cur[0])
+	}
+	t.mu.Unlock()
+
+	if !logNextSched {
+		return true
+	}
+	nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
+	msg := s.Name + " is within its minimum gap, next schedule to run on: " + nextSched
+	logger().Infow(msg, s.logKV()...)
+	color.Magenta(msg)
+	return true
+}
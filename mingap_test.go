@@ -0,0 +1,56 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMinGapWithTrigger(t *testing.T) {
+	const gap = 200 * time.Millisecond
+	ts := newTestScheduler()
+	var starts, ends []time.Time
+	if _, err := ts.addTask(newTestTask("rate-limited").Frequently().Seconds(1).MinGap(gap).ExecFunc(func() {
+		starts = append(starts, time.Now())
+		ends = append(ends, time.Now())
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "rate-limited")
+	ts.RunPending()
+
+	// A manual trigger right after the scheduled run is deferred to the end of the gap
+	if err := ts.Trigger("rate-limited"); err != nil {
+		t.Fatal(err)
+	}
+	ts.RunPending()
+	if len(starts) != 1 {
+		t.Fatalf("%d runs, want the triggered run to wait for the gap", len(starts))
+	}
+	info, _ := ts.Info("rate-limited")
+	if info.NextRun.Sub(ends[0]) < gap {
+		t.Errorf("deferred to %v, want at least %v after the end of the run", info.NextRun, gap)
+	}
+
+	time.Sleep(time.Until(info.NextRun))
+	ts.RunPending()
+	if len(starts) != 2 {
+		t.Fatalf("%d runs, want the triggered run once the gap has passed", len(starts))
+	}
+
+	// Then a scheduled run that is due right away waits for the gap too
+	makeDue(t, ts, "rate-limited")
+	ts.RunPending()
+	if len(starts) != 2 {
+		t.Fatalf("%d runs, want the scheduled run to wait for the gap", len(starts))
+	}
+	time.Sleep(gap)
+	ts.RunPending()
+	if len(starts) != 3 {
+		t.Fatalf("%d runs, want the scheduled run once the gap has passed", len(starts))
+	}
+	for i := 1; i < len(starts); i++ {
+		if d := starts[i].Sub(ends[i-1]); d < gap {
+			t.Errorf("run %d starts %v after the previous one ended, want at least %v", i+1, d, gap)
+		}
+	}
+}
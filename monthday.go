@@ -0,0 +1,70 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// MonthDayError is the error of a monthly task in the strict mode whose day doesn't exist in the month
+type MonthDayError struct {
+	Day   int
+	Month time.Month
+	Year  int
+}
+
+func (e *MonthDayError) Error() string {
+	return fmt.Sprintf("day %d doesn't exist in %s %d", e.Day, e.Month, e.Year)
+}
+
+// StrictMonthDay method stops the monthly task from clamping the day of the 'Every' method to the last day
+// of a shorter month, e.g the 31st in February. 'AddTask' returns a '*MonthDayError' if the day doesn't exist
+// in the month of the first run, then the later months without the day are skipped.
+func (s *Tasks) StrictMonthDay() *Tasks {
+	s.strictMonthDay = true
+	return s
+}
+
+// strictMonthDayError checks if the day of the strict monthly task exists in the month of its first run
+func (s *Tasks) strictMonthDayError(now time.Time) error {
+	if !s.strictMonthDay || s.RunType != _monthly || s.requestedDay <= 0 {
+		return nil
+	}
+	today := now.In(s.location())
+	month := time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location())
+	if daysIn(month) < s.requestedDay {
+		return &MonthDayError{Day: s.requestedDay, Month: month.Month(), Year: month.Year()}
+	}
+	return nil
+}
+
+// runDay returns the day of the month the monthly task runs on, the strict task keeps the day of the
+// 'Every' method as given while the others use the day clamped to the month it was set in
+func (s *Tasks) runDay() int {
+	if s.strictMonthDay && s.requestedDay > 0 {
+		return s.requestedDay
+	}
+	return s.monthDay
+}
+
+// strictMonthlyRun returns the next run on the exact day of the strict monthly task after the month of
+// the 'today' time, the months without the day are skipped
+func (s *Tasks) strictMonthlyRun(today time.Time, runHour, runMinute int) time.Time {
+	for i := 1; i <= 12; i++ {
+		month := time.Date(today.Year(), today.Month()+time.Month(i), 1, 0, 0, 0, 0, today.Location())
+		if daysIn(month) >= s.requestedDay {
+			return time.Date(month.Year(), month.Month(), s.requestedDay, runHour, runMinute, 0, 0, today.Location())
+		}
+		err := &MonthDayError{Day: s.requestedDay, Month: month.Month(), Year: month.Year()}
+		msg := s.Name + " skips the month: " + err.Error()
+		logger().Warnw(msg, s.logKV()...)
+		color.Yellow(msg)
+	}
+	return time.Time{}
+}
+
+// daysIn returns the number of days in the month of the DateTime
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, month.Location()).Day()
+}
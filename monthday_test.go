@@ -0,0 +1,66 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStrictMonthDayFebruary(t *testing.T) {
+	january := time.Date(2031, time.January, 15, 12, 0, 0, 0, time.Local)
+
+	strict := newTestTask("strict").Monthly().Every(31).At("10:00").StrictMonthDay()
+	var dayErr *MonthDayError
+	if err := strict.strictMonthDayError(january); !errors.As(err, &dayErr) {
+		t.Fatalf("strict error %v, want a *MonthDayError for February", err)
+	}
+	if dayErr.Day != 31 || dayErr.Month != time.February || dayErr.Year != 2031 {
+		t.Errorf("error %+v, want day 31 of February 2031", dayErr)
+	}
+	// Once running, February is skipped instead of clamped
+	if next := strict.strictMonthlyRun(january, 10, 0); !next.Equal(time.Date(2031, time.March, 31, 10, 0, 0, 0, time.Local)) {
+		t.Errorf("strict run after January is %v, want March 31", next)
+	}
+
+	lenient := newTestTask("lenient").Monthly().Every(31).At("10:00")
+	if err := lenient.strictMonthDayError(january); err != nil {
+		t.Errorf("lenient error %v, want none", err)
+	}
+	if _, err := newTestScheduler().addTask(lenient.ExecFunc(func() {})); err != nil {
+		t.Errorf("lenient task is not added: %v", err)
+	}
+
+	// A day that exists in every month is never an error
+	ok := newTestTask("ok").Monthly().Every(28).At("10:00").StrictMonthDay()
+	if err := ok.strictMonthDayError(january); err != nil {
+		t.Errorf("day 28 error %v, want none", err)
+	}
+}
+
+func TestStrictMonthDayIntrospection(t *testing.T) {
+	s := newTestTask("strict").Monthly().Every(30).At("09:00").StrictMonthDay().ExecFunc(func() {})
+	s.monthDay = 28 // As clamped by 'Every' in February
+	ts := newTestScheduler()
+	if _, err := ts.addTask(s); err != nil {
+		t.Skip(err) // Day 30 doesn't exist in the month of the first run
+	}
+	info, _ := ts.Info("strict")
+	next := info.NextRun
+	if next.Day() != 30 {
+		t.Fatalf("next run %v, want on the 30th", next)
+	}
+
+	if info.MonthDay != 30 {
+		t.Errorf("info month day %d, want 30", info.MonthDay)
+	}
+	if desc, _ := ts.Describe("strict"); desc != "Monthly on day 30 at 09:00" {
+		t.Errorf("described as %q, want on day 30", desc)
+	}
+	if ok, _ := ts.WouldRunAt("strict", next); !ok {
+		t.Errorf("doesn't run at its next run %v", next)
+	}
+	clamped := time.Date(next.Year(), next.Month(), 28, 9, 0, 0, 0, next.Location())
+	if ok, _ := ts.WouldRunAt("strict", clamped); ok {
+		t.Errorf("runs at the clamped day %v", clamped)
+	}
+}
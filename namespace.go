@@ -0,0 +1,78 @@
+package isked
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Namespace gets or creates the isolated sub-scheduler with its own task list, its tasks
+// are executed by the same 'Run' loop of this task scheduler.
+func (t *TaskScheduler) Namespace(name string) *TaskScheduler {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if ns, ok := t.namespaces[name]; ok {
+		return ns
+	}
+	if t.namespaces == nil {
+		t.namespaces = make(map[string]*TaskScheduler)
+	}
+	ns := &TaskScheduler{
+		TaskList: make(map[string][]Tasks),
+		parent:   t,
+	}
+	t.namespaces[name] = ns
+	return ns
+}
+
+// RemoveByNamespace removes the sub-scheduler and all of its tasks, it returns the number of tasks
+// removed including its own namespaces, 0 if the namespace doesn't exist.
+func (t *TaskScheduler) RemoveByNamespace(name string) int {
+	t.mu.Lock()
+	ns, ok := t.namespaces[name]
+	delete(t.namespaces, name)
+	t.mu.Unlock()
+	if !ok {
+		return 0
+	}
+
+	removed := ns.taskCount()
+	msg := fmt.Sprintf("namespace %s is removed with %d tasks", name, removed)
+	logger().Infow(msg, "log_time", time.Now().Format(logDateTimeFormat))
+	color.Cyan(msg)
+	return removed
+}
+
+// taskCount returns the number of tasks of the task scheduler and all of its namespaces
+func (t *TaskScheduler) taskCount() int {
+	count := 0
+	for _, ts := range t.withNamespaces() {
+		ts.mu.RLock()
+		count += len(ts.TaskList)
+		ts.mu.RUnlock()
+	}
+	return count
+}
+
+// withNamespaces returns the task scheduler followed by all of its namespaces sorted by name
+func (t *TaskScheduler) withNamespaces() []*TaskScheduler {
+	t.mu.RLock()
+	names := make([]string, 0, len(t.namespaces))
+	for name := range t.namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	children := make([]*TaskScheduler, 0, len(names))
+	for _, name := range names {
+		children = append(children, t.namespaces[name])
+	}
+	t.mu.RUnlock()
+
+	all := []*TaskScheduler{t}
+	for _, ns := range children {
+		all = append(all, ns.withNamespaces()...)
+	}
+	return all
+}
@@ -0,0 +1,142 @@
+package isked
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNamespacesIndependent(t *testing.T) {
+	ts := newTestScheduler()
+	acme, globex := ts.Namespace("acme"), ts.Namespace("globex")
+	if ts.Namespace("acme") != acme {
+		t.Fatal("Namespace doesn't return the existing namespace")
+	}
+
+	runs := make(map[string]int)
+	for tenant, ns := range map[string]*TaskScheduler{"acme": acme, "globex": globex} {
+		tenant := tenant
+		if _, err := ns.AddTask(newTestTask("invoice").Frequently().Minutes(1).ExecFunc(func() { runs[tenant]++ })); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Same task name in each namespace, pausing one doesn't touch the other
+	if err := acme.Pause("invoice"); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, acme, "invoice")
+	makeDue(t, globex, "invoice")
+	ts.RunPending()
+	if runs["acme"] != 0 || runs["globex"] != 1 {
+		t.Errorf("runs %v, want only globex to run from the shared loop", runs)
+	}
+
+	if removed := ts.RemoveByNamespace("acme"); removed != 1 {
+		t.Errorf("RemoveByNamespace removed %d tasks, want 1", removed)
+	}
+	if removed := ts.RemoveByNamespace("acme"); removed != 0 {
+		t.Errorf("second RemoveByNamespace removed %d tasks, want 0", removed)
+	}
+	if _, ok := globex.Get("invoice"); !ok {
+		t.Error("task of the other namespace is removed")
+	}
+	if got := ts.taskCount(); got != 1 {
+		t.Errorf("taskCount = %d, want 1", got)
+	}
+
+	if removed := globex.Reset(); removed != 1 {
+		t.Errorf("Reset of the namespace removed %d tasks, want 1", removed)
+	}
+	if got := ts.taskCount(); got != 0 {
+		t.Errorf("taskCount = %d after the reset, want 0", got)
+	}
+}
+
+func TestBulkCounts(t *testing.T) {
+	ts := newTestScheduler()
+	add := func(ns *TaskScheduler, names ...string) {
+		for _, name := range names {
+			if _, err := ns.AddTask(newTestTask(name).Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	acme := ts.Namespace("acme")
+	add(ts, "root-1", "root-2")
+	add(acme, "invoice", "report", "backup")
+	add(acme.Namespace("eu"), "invoice")
+
+	if n := ts.RemoveByNamespace("missing"); n != 0 {
+		t.Errorf("missing namespace removes %d tasks, want 0", n)
+	}
+	if n := ts.RemoveByNamespace("acme"); n != 4 {
+		t.Errorf("namespace removes %d tasks, want 4 including its own namespace", n)
+	}
+
+	add(ts.Namespace("globex"), "invoice")
+	if n := ts.Reset(); n != 3 {
+		t.Errorf("Reset removes %d tasks, want 3 including the namespace", n)
+	}
+	if n := ts.Reset(); n != 0 {
+		t.Errorf("second Reset removes %d tasks, want 0", n)
+	}
+}
+
+func TestRunPendingOrder(t *testing.T) {
+	ts := newTestScheduler()
+	var order []string
+	add := func(ns *TaskScheduler, prefix, name string) {
+		if _, err := ns.AddTask(newTestTask(name).Frequently().Minutes(1).ExecFunc(func() { order = append(order, prefix+name) })); err != nil {
+			t.Fatal(err)
+		}
+		makeDue(t, ns, name)
+	}
+	// Added out of order, the map of the task list has no order of its own
+	zeta, alpha := ts.Namespace("zeta"), ts.Namespace("alpha")
+	for _, name := range []string{"delta", "alpha", "charlie", "bravo"} {
+		add(zeta, "zeta/", name)
+		add(ts, "", name)
+		add(alpha, "alpha/", name)
+	}
+
+	want := []string{
+		"alpha", "bravo", "charlie", "delta",
+		"alpha/alpha", "alpha/bravo", "alpha/charlie", "alpha/delta",
+		"zeta/alpha", "zeta/bravo", "zeta/charlie", "zeta/delta",
+	}
+	for i := 0; i < 5; i++ {
+		order = nil
+		ts.RunPending()
+		if !reflect.DeepEqual(order, want) {
+			t.Fatalf("pass %d ran %v, want %v", i+1, order, want)
+		}
+		for _, ns := range []*TaskScheduler{ts, alpha, zeta} {
+			for _, name := range []string{"alpha", "bravo", "charlie", "delta"} {
+				makeDue(t, ns, name)
+			}
+		}
+	}
+}
+
+func TestDuplicateInNamespace(t *testing.T) {
+	ts := newTestScheduler()
+	billing := ts.Namespace("billing")
+	if _, err := billing.AddTask(newTestTask("Invoice").Daily().At("09:00").ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := billing.Info("Invoice")
+
+	_, err := billing.AddTask(newTestTask("Invoice").Frequently().Minutes(5).ExecFunc(func() {}))
+	if !errors.Is(err, ErrTaskExists) {
+		t.Errorf("second add error %v, want ErrTaskExists", err)
+	}
+	if info, _ := billing.Info("Invoice"); billing.taskCount() != 1 || info.ID != first.ID || !info.NextRun.Equal(first.NextRun) {
+		t.Errorf("first task is replaced by %+v", info)
+	}
+
+	// The same name in another namespace is a different task
+	if _, err := ts.Namespace("shipping").AddTask(newTestTask("Invoice").Daily().At("09:00").ExecFunc(func() {})); err != nil {
+		t.Errorf("same name in another namespace: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package isked
+
+import (
+	"time"
+)
+
+// NextFunc method uses the user's defined func to compute how long to wait before each run,
+// including the first one. A zero or negative duration stops the task, on the first call the task is not
+// added and 'ErrNoFirstRun' is returned.
+//
+// It can be combined with 'FirstOf' for the composite schedules, e.g to run at 9am in whichever
+// region opens first:
+//
+//	isked.TaskName("Open").NextFunc(func() time.Duration {
+//		return time.Until(isked.FirstOf(nineAM(tokyo), nineAM(london), nineAM(newYork)))
+//	}).ExecFunc(myFunc1).AddTask()
+func (s *Tasks) NextFunc(fn func() time.Duration) *Tasks {
+	if s.RunType == "" {
+		s.RunType = _frequently
+	}
+	s.nextFunc = fn
+	s.nextAt = nil
+	return s
+}
+
+// NextAt method uses the user's defined func to compute the DateTime of each run, including the first one,
+// e.g the next market open pulled from an API. A zero time stops the task, a time in the past runs it right away.
+// The task is not added if the first call returns the zero time, 'ErrNoFirstRun' is returned instead.
+func (s *Tasks) NextAt(fn func() time.Time) *Tasks {
+	s.NextFunc(func() time.Duration { return time.Until(fn()) }) // Keeps the same restrictions as the 'NextFunc' method
+	s.nextAt = fn
+	return s
+}
+
+// FirstOf returns the earliest time that is still in the future, or the zero time if there's none.
+func FirstOf(times ...time.Time) time.Time {
+	now := time.Now()
+	var first time.Time
+	for _, e := range times {
+		if !e.After(now) {
+			continue
+		}
+		if first.IsZero() || e.Before(first) {
+			first = e
+		}
+	}
+	return first
+}
@@ -0,0 +1,70 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNoFirstRun(t *testing.T) {
+	ts := newTestScheduler()
+	tasks := []*Tasks{
+		newTestTask("next-at").NextAt(func() time.Time { return time.Time{} }).ExecFunc(func() {}),
+		newTestTask("next-func").NextFunc(func() time.Duration { return 0 }).ExecFunc(func() {}),
+	}
+	for _, s := range tasks {
+		if _, err := ts.addTask(s); !errors.Is(err, ErrNoFirstRun) {
+			t.Errorf("%s: error %v, want ErrNoFirstRun", s.Name, err)
+		}
+		if _, ok := ts.Get(s.Name); ok {
+			t.Errorf("%s: added without a first run", s.Name)
+		}
+	}
+}
+
+func TestNextAt(t *testing.T) {
+	ts := newTestScheduler()
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	first, err := ts.addTask(newTestTask("next-at").NextAt(func() time.Time { return at }).ExecFunc(func() {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Equal(at) {
+		t.Errorf("first run %v, want %v", first, at)
+	}
+}
+
+func TestFirstOf(t *testing.T) {
+	now := time.Now()
+	soon, later := now.Add(time.Hour), now.Add(2*time.Hour)
+	tests := []struct {
+		name  string
+		times []time.Time
+		want  time.Time
+	}{
+		{"earliest future", []time.Time{later, soon}, soon},
+		{"skips the past", []time.Time{now.Add(-time.Hour), later}, later},
+		{"skips the zero time", []time.Time{{}, soon}, soon},
+		{"all in the past", []time.Time{now.Add(-time.Minute)}, time.Time{}},
+		{"none", nil, time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := FirstOf(tt.times...); !got.Equal(tt.want) {
+			t.Errorf("%s: FirstOf = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFirstOfNextFunc(t *testing.T) {
+	ts := newTestScheduler()
+	tokyo, london := time.Now().Add(3*time.Hour), time.Now().Add(time.Hour)
+	first, err := ts.addTask(newTestTask("open").NextFunc(func() time.Duration {
+		return time.Until(FirstOf(tokyo, london))
+	}).ExecFunc(func() {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := first.Sub(london); d < -time.Second || d > time.Second {
+		t.Errorf("first run %v, want the earliest region at %v", first, london)
+	}
+}
@@ -0,0 +1,19 @@
+package isked
+
+// List of the reasons passed to the 'OnEnd' func
+const (
+	EndLimit     = "limit"       // reached its limit of runs
+	EndLastRun   = "last_run"    // ran on the last of its dates
+	EndNoNextRun = "no_next_run" // the 'NextFunc' or 'NextAt' func has no next run
+	EndDateRange = "date_range"  // its next run is after the end of its date range
+	EndSuccess   = "success"     // succeeded with the 'UntilSuccess' method
+	EndStopped   = "stopped"     // stopped as directed by its own function
+)
+
+// OnEnd method sets the func to be called once when the scheduler removes the task for good, e.g to release
+// its resources after its last run. The reason is one of the 'End' constants, e.g 'EndLimit'. It's not
+// called when the task is removed by the user, e.g with 'Reset' or 'ReloadFrom'.
+func (s *Tasks) OnEnd(fn func(reason string)) *Tasks {
+	s.onEnd = fn
+	return s
+}
@@ -0,0 +1,63 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnEndReasons(t *testing.T) {
+	now := time.Now()
+	waits := []time.Duration{time.Hour, 0}
+	nextWait := func() time.Duration {
+		w := waits[0]
+		waits = waits[1:]
+		return w
+	}
+	tests := []struct {
+		task *Tasks
+		want string
+	}{
+		{newTestTask("limit").Frequently().Minutes(1).Limit(2), EndLimit},
+		{newTestTask("dates").OnDates(now.Add(time.Hour), now.Add(2*time.Hour)), EndLastRun},
+		{newTestTask("next-func").NextFunc(nextWait), EndNoNextRun},
+		{newTestTask("date-range").Frequently().Minutes(1).Between(now.Add(-time.Hour), now.Add(90*time.Second)), EndDateRange},
+		{newTestTask("success").Frequently().Minutes(1).UntilSuccess(), EndSuccess},
+	}
+	for _, tt := range tests {
+		ts := newTestScheduler()
+		var reasons []string
+		if _, err := ts.addTask(tt.task.ExecFunc(func() {}).OnEnd(func(r string) { reasons = append(reasons, r) })); err != nil {
+			t.Fatalf("%s: %v", tt.task.Name, err)
+		}
+		if tt.want == EndDateRange {
+			// The end moves before the next run, as if the time passed
+			ts.mu.Lock()
+			ts.TaskList[tt.task.Name][0].until = time.Now().Add(30 * time.Second)
+			ts.mu.Unlock()
+		}
+		for i := 0; i < 3; i++ {
+			if _, ok := ts.Get(tt.task.Name); !ok {
+				break
+			}
+			makeDue(t, ts, tt.task.Name)
+			ts.RunPending()
+		}
+		if _, ok := ts.Get(tt.task.Name); ok {
+			t.Errorf("%s: task is not ended", tt.task.Name)
+		}
+		if len(reasons) != 1 || reasons[0] != tt.want {
+			t.Errorf("%s: OnEnd called with %v, want once with %q", tt.task.Name, reasons, tt.want)
+		}
+	}
+
+	// Not called when the user removes the task
+	ts := newTestScheduler()
+	called := false
+	if _, err := ts.addTask(newTestTask("removed").Frequently().Minutes(1).ExecFunc(func() {}).OnEnd(func(string) { called = true })); err != nil {
+		t.Fatal(err)
+	}
+	ts.RemoveTask("removed")
+	if called {
+		t.Error("OnEnd is called for a task removed by the user")
+	}
+}
@@ -0,0 +1,52 @@
+package isked
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// SaveOneTime writes the onetime tasks that are still to run as JSON, the recurring tasks and the
+// onetime tasks with a past next run are left out. Use 'LoadOneTime' to add them back, e.g after a restart.
+func (t *TaskScheduler) SaveOneTime(w io.Writer) error {
+	now := time.Now()
+	t.mu.RLock()
+	defs := []TaskDef{}
+	for _, e := range t.TaskList {
+		for i := range e {
+			if e[i].RunType == _onetime && e[i].nextRunTime.After(now) {
+				defs = append(defs, e[i].def())
+			}
+		}
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return json.NewEncoder(w).Encode(defs)
+}
+
+// LoadOneTime adds the onetime tasks written by 'SaveOneTime' with their func from the registry using the
+// task name, the tasks with a past next run are dropped. It stops at the first task that can't be added.
+func (t *TaskScheduler) LoadOneTime(r io.Reader, registry map[string]FuncToExec) error {
+	var defs []TaskDef
+	if err := json.NewDecoder(r).Decode(&defs); err != nil {
+		return fmt.Errorf("invalid onetime tasks: %w", err)
+	}
+
+	now := time.Now()
+	for _, def := range defs {
+		if def.RunType != RunOneTime || !def.NextRun.After(now) {
+			continue
+		}
+		fn, ok := registry[def.Name]
+		if !ok || fn == nil {
+			return fmt.Errorf("%s: missing function to execute in the registry", def.Name)
+		}
+		if err := t.Import(def, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package isked
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOnDates(t *testing.T) {
+	ts := newTestScheduler()
+	base := time.Now().Add(time.Hour).Truncate(time.Second)
+	d1, d2, d3 := base, base.Add(time.Hour), base.Add(2*time.Hour)
+	runs := 0
+	var reason string
+	// Given out of order and with a past DateTime that is ignored
+	s := newTestTask("reminders").OnDates(d3, base.Add(-2*time.Hour), d1, d2).
+		ExecFunc(func() { runs++ }).OnEnd(func(r string) { reason = r })
+	if _, err := ts.addTask(s); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []time.Time{d1, d2, d3} {
+		info, ok := ts.Info("reminders")
+		if !ok {
+			t.Fatalf("removed before the run on %v", want)
+		}
+		if !info.NextRun.Equal(want) {
+			t.Errorf("next run %v, want %v", info.NextRun, want)
+		}
+		makeDue(t, ts, "reminders")
+		ts.RunPending()
+		if runs != i+1 {
+			t.Errorf("%d runs after the date %d, want %d", runs, i+1, i+1)
+		}
+	}
+	if _, ok := ts.Get("reminders"); ok {
+		t.Error("still in the task list after the last date")
+	}
+	if reason != EndLastRun {
+		t.Errorf("end reason %q, want %q", reason, EndLastRun)
+	}
+}
+
+func TestOnDatesAllPast(t *testing.T) {
+	ts := newTestScheduler()
+	s := newTestTask("late").OnDates(time.Now().Add(-time.Minute)).ExecFunc(func() {})
+	if _, err := ts.addTask(s); !hasProblem(err, "OnDates") {
+		t.Errorf("error %v, want an OnDates problem", err)
+	}
+}
+
+func TestNextWeekdayRun(t *testing.T) {
+	now := time.Date(2030, time.January, 2, 12, 0, 0, 0, time.Local) // Wednesday noon
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		for _, at := range []string{"09:00", "17:00"} {
+			s := newTestTask("reminder").NextWeekday(d).At(at)
+			got := s.nextWeekdayRun(now)
+
+			days := (int(d) - int(now.Weekday()) + 7) % 7
+			if days == 0 && at == "09:00" {
+				days = 7 // Today's time has passed, next week
+			}
+			hour := 9
+			if at == "17:00" {
+				hour = 17
+			}
+			want := time.Date(2030, time.January, 2+days, hour, 0, 0, 0, time.Local)
+			if !got.Equal(want) || got.Weekday() != d {
+				t.Errorf("%v at %s: %v, want %v", d, at, got, want)
+			}
+		}
+	}
+}
+
+func TestNextWeekday(t *testing.T) {
+	ts := newTestScheduler()
+	first, err := ts.addTask(newTestTask("friday").NextWeekday(time.Friday).At("17:00").ExecFunc(func() {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if at := first.In(time.Local); at.Weekday() != time.Friday || at.Hour() != 17 || !at.After(time.Now()) {
+		t.Errorf("first run %v, want the next Friday at 17:00", at)
+	}
+	if d := time.Until(first); d > 7*24*time.Hour {
+		t.Errorf("first run in %v, want within a week", d)
+	}
+}
+
+func TestSaveLoadOneTime(t *testing.T) {
+	src := newTestScheduler()
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	for _, s := range []*Tasks{
+		newTestTask("reminder-1").OneTime(at.Unix()),
+		newTestTask("reminder-2").OneTime(at.Add(time.Hour).Unix()),
+		newTestTask("past").OneTime(at.Unix()),
+		newTestTask("recurring").Frequently().Minutes(5),
+		newTestTask("daily").Daily().At("10:00"),
+	} {
+		if _, err := src.addTask(s.ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setNextRun(t, src, "past", time.Now().Add(-time.Minute))
+
+	var buf bytes.Buffer
+	if err := src.SaveOneTime(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var saved []TaskDef
+	if err := json.Unmarshal(buf.Bytes(), &saved); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 2 || saved[0].Name != "reminder-1" || saved[1].Name != "reminder-2" {
+		t.Fatalf("saved %+v, want only the future onetime tasks", saved)
+	}
+
+	dst := newTestScheduler()
+	registry := map[string]FuncToExec{"reminder-1": func() {}, "reminder-2": func() {}}
+	if err := dst.LoadOneTime(bytes.NewReader(buf.Bytes()), registry); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(dst.TaskList); n != 2 {
+		t.Errorf("%d tasks loaded, want 2", n)
+	}
+	for name, want := range map[string]time.Time{"reminder-1": at, "reminder-2": at.Add(time.Hour)} {
+		if info, ok := dst.Info(name); !ok || !info.NextRun.Equal(want) {
+			t.Errorf("%s: loaded next run %v, want %v", name, info.NextRun, want)
+		}
+	}
+
+	// A reminder that became past while it was saved is dropped
+	saved[0].NextRun = time.Now().Add(-time.Minute)
+	data, _ := json.Marshal(saved)
+	late := newTestScheduler()
+	if err := late.LoadOneTime(bytes.NewReader(data), registry); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := late.Get("reminder-1"); ok || len(late.TaskList) != 1 {
+		t.Errorf("%d tasks loaded, want only the future reminder-2", len(late.TaskList))
+	}
+
+	if err := newTestScheduler().LoadOneTime(bytes.NewReader(buf.Bytes()), nil); err == nil {
+		t.Error("tasks loaded without their func")
+	}
+	if err := newTestScheduler().LoadOneTime(strings.NewReader("not json"), registry); err == nil {
+		t.Error("invalid data is loaded")
+	}
+}
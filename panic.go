@@ -0,0 +1,44 @@
+package isked
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/fatih/color"
+)
+
+// PanicPolicy is what to do when the user's defined func of a task panics
+type PanicPolicy int
+
+// List of the panic policies
+const (
+	PanicRecover   PanicPolicy = iota // recover and report the panic as the error of the run
+	PanicPropagate                    // let the panic crash the process, e.g for the fail-fast setups
+)
+
+// SetPanicPolicy sets what to do when the user's defined func of a task panics, default is 'PanicRecover'
+func (t *TaskScheduler) SetPanicPolicy(policy PanicPolicy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.panicPolicy = policy
+}
+
+// guard calls the run of the task and turns its panic into an error unless the policy is to propagate it
+func (t *TaskScheduler) guard(s *Tasks, run func() error) (err error) {
+	t.mu.RLock()
+	policy := t.panicPolicy
+	t.mu.RUnlock()
+	if policy == PanicPropagate {
+		return run()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from panic: %v", r)
+			msg := s.Name + " panics: " + fmt.Sprint(r)
+			logger().Errorw(msg, append(s.logKV(), "stack", string(debug.Stack()))...)
+			color.Red(msg)
+		}
+	}()
+	return run()
+}
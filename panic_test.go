@@ -0,0 +1,38 @@
+package isked
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPanicPolicy(t *testing.T) {
+	// Recovered by default and reported as the error of the run
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("boom").Frequently().Minutes(1).ExecFunc(func() { panic("boom") })); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "boom")
+	ts.RunPending()
+	err, _, _ := ts.LastError("boom")
+	if err == nil || !strings.Contains(err.Error(), "recovered from panic: boom") {
+		t.Errorf("last error %v, want the recovered panic", err)
+	}
+	if info, _ := ts.Info("boom"); info.Stats.Runs != 1 || info.Stats.Errors != 1 {
+		t.Errorf("stats %+v, want the failed run", info.Stats)
+	}
+
+	// Propagated to the caller of the run
+	ts = newTestScheduler()
+	ts.SetPanicPolicy(PanicPropagate)
+	if _, err := ts.addTask(newTestTask("boom").Frequently().Minutes(1).ExecFunc(func() { panic("boom") })); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "boom")
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want the panic of the task to propagate", r)
+		}
+	}()
+	ts.RunPending()
+	t.Error("RunPending returns after a panic with the propagate policy")
+}
@@ -0,0 +1,86 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// ResumePolicy is what to do with the run that was missed while the task was paused
+type ResumePolicy int
+
+// List of the resume policies
+const (
+	ResumeSkipMissed ResumePolicy = iota // skip the missed run, the task runs again on its next schedule
+	ResumeRunMissed                      // run the missed run once right away, then follow the schedule as usual
+)
+
+// Pause holds the task using the task name or the task ID, it doesn't run until it's resumed
+// even if its schedule is due.
+func (t *TaskScheduler) Pause(taskName string) error {
+	if t.isClosed() {
+		return ErrSchedulerClosed
+	}
+	t.mu.Lock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		t.mu.Unlock()
+		return fmt.Errorf("%s: %w", taskName, ErrTaskNotFound)
+	}
+	s := &taskData[0]
+	s.paused = true
+	t.emit(ChangePaused, s)
+	kv := s.logKV()
+	name := s.Name
+	t.mu.Unlock()
+	t.notify()
+
+	msg := name + " is paused"
+	logger().Infow(msg, kv...)
+	color.Yellow(msg)
+	return nil
+}
+
+// Resume releases the paused task using the task name or the task ID, the policy decides if the run that
+// was missed while it was paused, e.g the daily run of today, runs right away or is skipped. A missed
+// onetime run always runs right away.
+func (t *TaskScheduler) Resume(taskName string, policy ResumePolicy) error {
+	if t.isClosed() {
+		return ErrSchedulerClosed
+	}
+	now := time.Now()
+	t.mu.Lock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		t.mu.Unlock()
+		return fmt.Errorf("%s: %w", taskName, ErrTaskNotFound)
+	}
+	s := &taskData[0]
+	if !s.paused {
+		t.mu.Unlock()
+		return nil
+	}
+	s.paused = false
+	missed := !s.nextRunTime.IsZero() && !s.nextRunTime.After(now)
+	if missed && policy == ResumeSkipMissed && s.RunType != _onetime {
+		if next := s.nextSchedule(now); !next.IsZero() {
+			s.nextRunTime = next
+		}
+	}
+	if missed && (policy == ResumeRunMissed || s.RunType == _onetime) {
+		s.nextRunTime = now
+	}
+	t.emit(ChangeResumed, s)
+	nextSchedToRun := s.nextRunTime
+	kv := s.logKV()
+	name := s.Name
+	t.mu.Unlock()
+	t.notify()
+
+	nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
+	msg := name + " is resumed, next schedule to run on: " + nextSched
+	logger().Infow(msg, kv...)
+	color.Cyan(msg)
+	return nil
+}
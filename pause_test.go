@@ -0,0 +1,104 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResumePolicy(t *testing.T) {
+	slot := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	for _, tt := range []struct {
+		name   string
+		policy ResumePolicy
+		runs   int
+	}{
+		{"skip", ResumeSkipMissed, 0},
+		{"run", ResumeRunMissed, 1},
+	} {
+		ts := newTestScheduler()
+		runs := 0
+		if _, err := ts.addTask(newTestTask("daily").Daily().At(slot.Format("15:04")).ExecFunc(func() { runs++ })); err != nil {
+			t.Fatal(err)
+		}
+		if err := ts.Pause("daily"); err != nil {
+			t.Fatal(err)
+		}
+		// Its slot passes while it's paused
+		setNextRun(t, ts, "daily", slot)
+		ts.RunPending()
+		if runs != 0 {
+			t.Fatalf("%s: %d runs while paused, want 0", tt.name, runs)
+		}
+
+		if err := ts.Resume("daily", tt.policy); err != nil {
+			t.Fatal(err)
+		}
+		ts.RunPending()
+		ts.RunPending()
+		if runs != tt.runs {
+			t.Errorf("%s: %d runs after resuming, want %d", tt.name, runs, tt.runs)
+		}
+		// Back on the normal cadence, tomorrow at the slot
+		info, _ := ts.Info("daily")
+		if want := slot.AddDate(0, 0, 1); !info.NextRun.Equal(want) {
+			t.Errorf("%s: next run %v, want %v", tt.name, info.NextRun, want)
+		}
+	}
+
+	// A missed onetime run always runs
+	ts := newTestScheduler()
+	runs := 0
+	if _, err := ts.addTask(newTestTask("once").OneTime(time.Now().Add(time.Hour).Unix()).ExecFunc(func() { runs++ })); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Pause("once"); err != nil {
+		t.Fatal(err)
+	}
+	setNextRun(t, ts, "once", slot)
+	if err := ts.Resume("once", ResumeSkipMissed); err != nil {
+		t.Fatal(err)
+	}
+	ts.RunPending()
+	if runs != 1 {
+		t.Errorf("%d runs of the missed onetime task, want 1", runs)
+	}
+}
+
+func TestPauseEverySecond(t *testing.T) {
+	ts := newTestScheduler()
+	runs := 0
+	if _, err := ts.addTask(newTestTask("tick").Frequently().Seconds(1).ExecFunc(func() { runs++ })); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Pause("tick"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ten windows are missed while it's paused, its next run is kept as is
+	missed := time.Now().Add(-10 * time.Second)
+	setNextRun(t, ts, "tick", missed)
+	for i := 0; i < 3; i++ {
+		ts.RunPending()
+	}
+	if runs != 0 {
+		t.Fatalf("%d runs while paused, want 0", runs)
+	}
+	if info, _ := ts.Info("tick"); !info.Paused || !info.NextRun.Equal(missed) {
+		t.Fatalf("paused %v with next run %v, want paused with next run %v", info.Paused, info.NextRun, missed)
+	}
+
+	// Resumed on the next slot, not once per missed window
+	if err := ts.Resume("tick", ResumeSkipMissed); err != nil {
+		t.Fatal(err)
+	}
+	ts.RunPending()
+	if runs != 0 {
+		t.Fatalf("%d runs right after resuming, want the next slot", runs)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	ts.RunPending()
+	ts.RunPending()
+	if runs != 1 {
+		t.Errorf("%d runs on the next slot after resuming, want 1", runs)
+	}
+}
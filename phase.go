@@ -0,0 +1,32 @@
+package isked
+
+import (
+	"time"
+)
+
+// Phase method offsets the runs of the frequently task within its interval from the midnight of its
+// timezone, e.g '.Frequently().Hours(1).Phase(17 * time.Minute)' runs every hour at 17 minutes past.
+// The phase must be less than the interval.
+func (s *Tasks) Phase(d time.Duration) *Tasks {
+	s.phase = d
+	s.hasPhase = true
+	return s
+}
+
+// EpochAligned method runs the frequently task on the multiples of its interval since the Unix epoch
+// instead of midnight, so the runs of all the instances land on the same boundaries without any
+// coordination, e.g every 15 minutes at :00, :15, :30 and :45 UTC. It can be combined with 'Phase'.
+func (s *Tasks) EpochAligned() *Tasks {
+	s.epochAligned = true
+	return s
+}
+
+// phasedRun returns the first run after the 'now' time that is on the interval boundary plus the phase
+func (s *Tasks) phasedRun(now time.Time) time.Time {
+	if s.epochAligned {
+		return alignedRun(time.Unix(0, 0).Add(s.phase), s.interval(), now)
+	}
+	today := now.In(s.location())
+	midnight := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	return alignedRun(midnight.Add(s.phase), s.interval(), now)
+}
@@ -0,0 +1,70 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPhase(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("hourly").Frequently().Hours(1).Phase(17 * time.Minute).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.addTask(newTestTask("quarter").Frequently().Minutes(15).Phase(5 * time.Minute).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first run is on the next boundary plus the phase
+	now := time.Now()
+	hourly, _ := ts.Info("hourly")
+	if at := hourly.NextRun; at.Minute() != 17 || at.Second() != 0 || !at.After(now) || at.Sub(now) > time.Hour {
+		t.Errorf("hourly first run %v, want the next :17", at)
+	}
+	quarter, _ := ts.Info("quarter")
+	if at := quarter.NextRun; at.Minute()%15 != 5 || at.Second() != 0 || !at.After(now) || at.Sub(now) > 15*time.Minute {
+		t.Errorf("quarter first run %v, want the next :05, :20, :35 or :50", at)
+	}
+
+	// Each run after it keeps the offset within its interval
+	cur, _ := ts.Get("hourly")
+	day := time.Date(2031, time.May, 6, 0, 0, 0, 0, time.Local)
+	for _, from := range []time.Time{day, day.Add(17 * time.Minute), day.Add(5*time.Hour + 40*time.Minute), day.Add(23*time.Hour + 30*time.Minute)} {
+		next := cur[0].nextSchedule(from)
+		if next.Minute() != 17 || next.Second() != 0 || !next.After(from) || next.Sub(from) > time.Hour {
+			t.Errorf("hourly run after %v is %v, want the next :17", from, next)
+		}
+	}
+
+	if _, err := ts.addTask(newTestTask("too-long").Frequently().Minutes(10).Phase(10 * time.Minute).ExecFunc(func() {})); err == nil {
+		t.Error("phase equal to the interval is accepted")
+	}
+}
+
+func TestEpochAligned(t *testing.T) {
+	ts := newTestScheduler()
+	intervals := map[string]time.Duration{"7s": 7 * time.Second, "13m": 13 * time.Minute, "5h": 5 * time.Hour}
+	for name, d := range intervals {
+		if _, err := ts.addTask(newTestTask(name).Frequently().Seconds(int(d / time.Second)).EpochAligned().ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ts.addTask(newTestTask("phased").Frequently().Minutes(15).EpochAligned().Phase(time.Minute).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	// The runs land on the multiples of the interval since the Unix epoch, in any timezone
+	for name, d := range intervals {
+		info, _ := ts.Info(name)
+		if info.NextRun.UnixNano()%int64(d) != 0 || !info.NextRun.After(now.Add(-time.Second)) || info.NextRun.Sub(now) > d {
+			t.Errorf("%s: first run %v, want the next epoch boundary", name, info.NextRun)
+		}
+		cur, _ := ts.Get(name)
+		if next := cur[0].nextSchedule(info.NextRun); next.Sub(info.NextRun) != d || next.UnixNano()%int64(d) != 0 {
+			t.Errorf("%s: run after %v is %v, want the next boundary", name, info.NextRun, next)
+		}
+	}
+	if info, _ := ts.Info("phased"); (info.NextRun.Unix()-60)%(15*60) != 0 {
+		t.Errorf("phased first run %v, want a minute after a 15 minutes epoch boundary", info.NextRun)
+	}
+}
@@ -0,0 +1,65 @@
+package isked
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WritePrometheus writes the metrics of the tasks in the Prometheus text format, e.g from an HTTP handler
+// of the "/metrics" path. Each task is labeled with its name, run type and interval, so the number of
+// series is bounded by the number of tasks. The interval label is empty if it's not the frequently option.
+func (t *TaskScheduler) WritePrometheus(w io.Writer) error {
+	t.mu.RLock()
+	list := make([]TaskInfo, 0, len(t.TaskList))
+	for _, e := range t.TaskList {
+		for i := range e {
+			list = append(list, e[i].info())
+		}
+	}
+	t.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	now := time.Now()
+	bw := bufio.NewWriter(w)
+	metric := func(name, kind, help string, value func(ti TaskInfo) (float64, bool)) {
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, ti := range list {
+			if v, ok := value(ti); ok {
+				fmt.Fprintf(bw, "%s%s %g\n", name, promLabels(ti), v)
+			}
+		}
+	}
+
+	metric("isked_task_next_run_timestamp_seconds", "gauge", "Unix time of the next run of the task.",
+		func(ti TaskInfo) (float64, bool) {
+			return float64(ti.NextRun.UnixNano()) / 1e9, !ti.NextRun.IsZero()
+		})
+	metric("isked_task_seconds_until_next_run", "gauge", "Seconds until the next run of the task, negative if it's overdue.",
+		func(ti TaskInfo) (float64, bool) {
+			return ti.NextRun.Sub(now).Seconds(), !ti.NextRun.IsZero()
+		})
+	metric("isked_task_runs_total", "counter", "Number of completed runs of the task.",
+		func(ti TaskInfo) (float64, bool) { return float64(ti.Stats.Runs), true })
+	metric("isked_task_errors_total", "counter", "Number of runs of the task that returned an error.",
+		func(ti TaskInfo) (float64, bool) { return float64(ti.Stats.Errors), true })
+	return bw.Flush()
+}
+
+// promLabels formats the labels of the task for the Prometheus text format
+func promLabels(ti TaskInfo) string {
+	interval := ""
+	if ti.Interval > 0 {
+		interval = ti.Interval.String()
+	}
+	return fmt.Sprintf(`{task="%s",run_type="%s",interval="%s"}`,
+		promEscape(ti.Name), promEscape(string(ti.RunType)), interval)
+}
+
+// promEscape escapes the label value for the Prometheus text format
+func promEscape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
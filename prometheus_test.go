@@ -0,0 +1,51 @@
+package isked
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("poll").Frequently().Minutes(5).ExecFuncErr(func() error { return errors.New("timeout") })); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.addTask(newTestTask(`say "hi"`).Daily().At("10:00").ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "poll")
+	ts.RunPending()
+	setNextRun(t, ts, "poll", time.Now().Add(time.Minute))
+
+	var buf bytes.Buffer
+	if err := ts.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE isked_task_next_run_timestamp_seconds gauge\n",
+		"# TYPE isked_task_seconds_until_next_run gauge\n",
+		"# TYPE isked_task_runs_total counter\n",
+		`isked_task_runs_total{task="poll",run_type="frequently",interval="5m0s"} 1` + "\n",
+		`isked_task_errors_total{task="poll",run_type="frequently",interval="5m0s"} 1` + "\n",
+		`isked_task_runs_total{task="say \"hi\"",run_type="daily",interval=""} 0` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output doesn't have %q:\n%s", want, out)
+		}
+	}
+
+	// One series per task and metric
+	prefix := `isked_task_seconds_until_next_run{task="poll",run_type="frequently",interval="5m0s"} `
+	if n := strings.Count(out, prefix); n != 1 {
+		t.Fatalf("%d series of the seconds until the next run, want 1", n)
+	}
+	line := out[strings.Index(out, prefix)+len(prefix):]
+	line = line[:strings.Index(line, "\n")]
+	if secs, err := time.ParseDuration(line + "s"); err != nil || secs <= 55*time.Second || secs > time.Minute {
+		t.Errorf("seconds until the next run %s, want about 60", line)
+	}
+}
@@ -0,0 +1,160 @@
+package isked
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskRecord is the flat definition of a task with its statistics, every field maps to a single column
+// so it can be stored in a database table. Use 'MarshalTask' and 'UnmarshalTask' to convert it.
+type TaskRecord struct {
+	ID             string
+	Name           string
+	RunType        string
+	Interval       int64 // frequently option only, in seconds
+	Weekday        int   // weekly option only, 0 is Sunday
+	MonthDay       int   // monthly option only
+	At             string
+	Location       string
+	Limit          int
+	RunCount       int
+	StartingFrom   time.Time
+	Until          time.Time
+	UntilSuccess   bool
+	BlackoutStart  string
+	BlackoutEnd    string
+	SkipBlackout   bool
+	RunAtStartup   bool
+	Backfill       int
+	FixedRate      bool
+	Compensate     bool
+	MinGap         int64 // in nanoseconds
+	Phase          int64 // frequently option only, in nanoseconds
+	HasPhase       bool
+	EpochAligned   bool
+	StrictMonthDay bool
+	Dates          string // onetime option only, comma separated RFC3339 DateTime
+	NextRun        time.Time
+	LastRun        time.Time
+	Created        time.Time
+	Runs           int
+	Errors         int
+	LastDuration   int64 // in nanoseconds
+	TotalDuration  int64 // in nanoseconds
+}
+
+// MarshalTask gets the flat record of the task using the task name or the task ID, tasks that use
+// the 'NextFunc' method can't be marshaled.
+func (t *TaskScheduler) MarshalTask(name string) (TaskRecord, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	taskData, ok := t.lookup(name)
+	if !ok || len(taskData) == 0 {
+		return TaskRecord{}, fmt.Errorf("%s: %w", name, ErrTaskNotFound)
+	}
+	s := taskData[0]
+	if s.nextFunc != nil {
+		return TaskRecord{}, fmt.Errorf("%s: tasks with a next func can't be marshaled", name)
+	}
+
+	def := s.def()
+	dates := make([]string, 0, len(def.Dates))
+	for _, d := range def.Dates {
+		dates = append(dates, d.Format(time.RFC3339Nano))
+	}
+	return TaskRecord{
+		ID:             def.ID,
+		Name:           def.Name,
+		RunType:        string(def.RunType),
+		Interval:       int64(def.Interval / time.Second),
+		Weekday:        int(def.Weekday),
+		MonthDay:       def.MonthDay,
+		At:             def.At,
+		Location:       def.Location,
+		Limit:          def.Limit,
+		RunCount:       def.RunCount,
+		StartingFrom:   def.StartingFrom,
+		Until:          def.Until,
+		UntilSuccess:   def.UntilSuccess,
+		BlackoutStart:  def.BlackoutStart,
+		BlackoutEnd:    def.BlackoutEnd,
+		SkipBlackout:   def.SkipBlackout,
+		RunAtStartup:   def.RunAtStartup,
+		Backfill:       def.Backfill,
+		FixedRate:      def.FixedRate,
+		Compensate:     def.Compensate,
+		MinGap:         int64(def.MinGap),
+		Phase:          int64(def.Phase),
+		HasPhase:       def.HasPhase,
+		EpochAligned:   def.EpochAligned,
+		StrictMonthDay: def.StrictMonthDay,
+		Dates:          strings.Join(dates, ","),
+		NextRun:        def.NextRun,
+		LastRun:        def.LastRun,
+		Created:        def.Created,
+		Runs:           s.stats.Runs,
+		Errors:         s.stats.Errors,
+		LastDuration:   int64(s.stats.LastDuration),
+		TotalDuration:  int64(s.stats.TotalDuration),
+	}, nil
+}
+
+// UnmarshalTask adds the task from its flat record with the function to be executed, like the 'Import'
+// method the next and last run are kept as is and the statistics are restored.
+func (t *TaskScheduler) UnmarshalTask(rec TaskRecord, fn FuncToExec) error {
+	def := TaskDef{
+		ID:             rec.ID,
+		Name:           rec.Name,
+		RunType:        RunType(rec.RunType),
+		Interval:       time.Duration(rec.Interval) * time.Second,
+		Weekday:        time.Weekday(rec.Weekday),
+		MonthDay:       rec.MonthDay,
+		At:             rec.At,
+		Location:       rec.Location,
+		Limit:          rec.Limit,
+		RunCount:       rec.RunCount,
+		StartingFrom:   rec.StartingFrom,
+		Until:          rec.Until,
+		UntilSuccess:   rec.UntilSuccess,
+		BlackoutStart:  rec.BlackoutStart,
+		BlackoutEnd:    rec.BlackoutEnd,
+		SkipBlackout:   rec.SkipBlackout,
+		RunAtStartup:   rec.RunAtStartup,
+		Backfill:       rec.Backfill,
+		FixedRate:      rec.FixedRate,
+		Compensate:     rec.Compensate,
+		MinGap:         time.Duration(rec.MinGap),
+		Phase:          time.Duration(rec.Phase),
+		HasPhase:       rec.HasPhase,
+		EpochAligned:   rec.EpochAligned,
+		StrictMonthDay: rec.StrictMonthDay,
+		NextRun:        rec.NextRun,
+		LastRun:        rec.LastRun,
+		Created:        rec.Created,
+	}
+	if len(rec.Dates) > 0 {
+		for _, v := range strings.Split(rec.Dates, ",") {
+			d, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return fmt.Errorf("%s: invalid date %q", rec.Name, v)
+			}
+			def.Dates = append(def.Dates, d)
+		}
+	}
+	if err := t.Import(def, fn); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cur, ok := t.TaskList[rec.Name]; ok && len(cur) > 0 {
+		cur[0].stats = TaskStats{
+			Runs:          rec.Runs,
+			Errors:        rec.Errors,
+			LastDuration:  time.Duration(rec.LastDuration),
+			TotalDuration: time.Duration(rec.TotalDuration),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+)
+
+// ReloadFrom replaces the task list with the definitions without stopping the running loop. New tasks are
+// added, absent tasks are removed and changed tasks are rescheduled while keeping their ID and statistics,
+// unchanged tasks keep their schedule and only get the func from the registry. The runs that are already in
+// progress are not interrupted. Nothing is changed if any definition is invalid or has no func in the registry.
+func (t *TaskScheduler) ReloadFrom(defs []TaskDef, registry map[string]FuncToExec) error {
+	if t.isClosed() {
+		return ErrSchedulerClosed
+	}
+	newTasks := make(map[string]*Tasks, len(defs))
+	for _, def := range defs {
+		if len(def.Name) == 0 {
+			return errors.New("missing task name")
+		}
+		if _, ok := newTasks[def.Name]; ok {
+			return fmt.Errorf("%s: %w", def.Name, ErrTaskExists)
+		}
+		fn, ok := registry[def.Name]
+		if !ok || fn == nil {
+			return fmt.Errorf("%s: missing function to execute in the registry", def.Name)
+		}
+		s, err := def.newTask(fn)
+		if err == nil {
+			err = s.validate()
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", def.Name, err)
+		}
+		newTasks[def.Name] = s
+	}
+
+	// Compute the schedules of the new and changed tasks outside the lock
+	t.mu.RLock()
+	if t.maxTasks > 0 && len(newTasks) > t.maxTasks {
+		t.mu.RUnlock()
+		return fmt.Errorf("%w, the limit is %d", ErrMaxTasks, t.maxTasks)
+	}
+	var changed []Tasks
+	for _, def := range defs {
+		cur, ok := t.TaskList[def.Name]
+		if ok && len(cur) > 0 && cur[0].nextFunc == nil && cur[0].def().sameSchedule(newTasks[def.Name].def()) {
+			continue
+		}
+		newTask := *newTasks[def.Name]
+		newTask.defaultLoc = t.defaultLoc
+		newTask.weekStart = t.weekStart
+		if def.NextRun.IsZero() {
+			newTask.nextRunTime = newTask.initialRun()
+		} else {
+			newTask.nextRunTime = def.NextRun
+			newTask.prepareBackfill(def.NextRun, time.Now())
+		}
+		newTask.lastRunTime = def.LastRun
+		newTask.created = time.Now()
+		if ok && len(cur) > 0 {
+			newTask.id = cur[0].id
+			newTask.stats = cur[0].stats
+			newTask.runCount = cur[0].runCount
+			newTask.lastRunTime = cur[0].lastRunTime
+			newTask.created = cur[0].created
+		} else if newTask.runAtStartup && def.LastRun.IsZero() {
+			newTask.firstRunAt = newTask.nextRunTime
+			newTask.nextRunTime = time.Now()
+		}
+		changed = append(changed, newTask)
+	}
+	t.mu.RUnlock()
+
+	var added, updated, removed []string
+	t.mu.Lock()
+	for name := range t.TaskList {
+		if _, ok := newTasks[name]; !ok {
+			t.emit(ChangeRemoved, &t.TaskList[name][0])
+			t.TaskList[name][0].ungate()
+			delete(t.TaskList, name)
+			removed = append(removed, name)
+		}
+	}
+	for name, s := range newTasks {
+		if cur, ok := t.TaskList[name]; ok && len(cur) > 0 {
+			cur[0].clearFuncs()
+			cur[0].ExecuteFunc = s.ExecuteFunc
+		}
+	}
+	for _, newTask := range changed {
+		if len(newTask.id) == 0 {
+			newTask.id = uuid.New().String()
+		}
+		kind := ChangeAdded
+		if cur, ok := t.TaskList[newTask.Name]; ok {
+			kind = ChangeRescheduled
+			if len(cur) > 0 {
+				cur[0].ungate() // Replaced by the task without its channel
+			}
+			updated = append(updated, newTask.Name)
+		} else {
+			added = append(added, newTask.Name)
+		}
+		t.TaskList[newTask.Name] = []Tasks{newTask}
+		t.emit(kind, &newTask)
+	}
+	t.mu.Unlock()
+	t.notify()
+	sort.Strings(removed)
+
+	msg := fmt.Sprintf("task list is reloaded, added: %v, updated: %v, removed: %v", added, updated, removed)
+	logger().Infow(msg, "log_time", time.Now().Format(logDateTimeFormat))
+	color.Cyan(msg)
+	return nil
+}
+
+// sameSchedule checks if both definitions have the same schedule, the run state is not compared
+func (def TaskDef) sameSchedule(o TaskDef) bool {
+	if def.RunType != o.RunType || def.Interval != o.Interval || def.Weekday != o.Weekday || def.WeekOfMonth != o.WeekOfMonth ||
+		def.MonthDay != o.MonthDay || def.At != o.At || def.Location != o.Location || def.Limit != o.Limit ||
+		!def.StartingFrom.Equal(o.StartingFrom) || !def.Until.Equal(o.Until) || def.UntilSuccess != o.UntilSuccess ||
+		def.BlackoutStart != o.BlackoutStart || def.BlackoutEnd != o.BlackoutEnd ||
+		def.SkipBlackout != o.SkipBlackout || def.Backfill != o.Backfill || def.FixedRate != o.FixedRate ||
+		def.Compensate != o.Compensate || def.MinGap != o.MinGap || def.Phase != o.Phase || def.HasPhase != o.HasPhase ||
+		def.EpochAligned != o.EpochAligned || def.StrictMonthDay != o.StrictMonthDay || len(def.Dates) != len(o.Dates) {
+		return false
+	}
+	for i := range def.Dates {
+		if !def.Dates[i].Equal(o.Dates[i]) {
+			return false
+		}
+	}
+	// The next run is the DateTime to execute the onetime option
+	return def.RunType != RunOneTime || def.NextRun.Equal(o.NextRun)
+}
@@ -0,0 +1,123 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReloadFrom(t *testing.T) {
+	ts := newTestScheduler()
+	for _, s := range []*Tasks{
+		newTestTask("keep").Frequently().Minutes(5),
+		newTestTask("change").Frequently().Minutes(5),
+		newTestTask("drop").Frequently().Minutes(5),
+	} {
+		if _, err := ts.addTask(s.ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	makeDue(t, ts, "change")
+	ts.RunPending()
+	before := map[string]TaskInfo{}
+	for _, name := range []string{"keep", "change"} {
+		before[name], _ = ts.Info(name)
+	}
+
+	keepRan := false
+	defs := []TaskDef{
+		{Name: "keep", RunType: RunFrequently, Interval: 5 * time.Minute},
+		{Name: "change", RunType: RunFrequently, Interval: 10 * time.Minute},
+		{Name: "new", RunType: RunFrequently, Interval: time.Hour},
+	}
+	registry := map[string]FuncToExec{
+		"keep":   func() { keepRan = true },
+		"change": func() {},
+		"new":    func() {},
+	}
+	if err := ts.ReloadFrom(defs, registry); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := ts.Get("drop"); ok {
+		t.Error("absent task is not removed")
+	}
+	if info, ok := ts.Info("new"); !ok || info.Interval != time.Hour {
+		t.Errorf("new task %+v, want an hourly task", info)
+	}
+
+	keep, _ := ts.Info("keep")
+	if keep.ID != before["keep"].ID || !keep.NextRun.Equal(before["keep"].NextRun) {
+		t.Errorf("unchanged task %s next %v, want %s next %v", keep.ID, keep.NextRun, before["keep"].ID, before["keep"].NextRun)
+	}
+	cur, _ := ts.Get("keep")
+	cur[0].ExecuteFunc()
+	if !keepRan {
+		t.Error("unchanged task doesn't get the func from the registry")
+	}
+
+	change, _ := ts.Info("change")
+	if change.Interval != 10*time.Minute {
+		t.Errorf("changed task interval %v, want 10m", change.Interval)
+	}
+	if change.ID != before["change"].ID || change.Stats.Runs != 1 || !change.LastRun.Equal(before["change"].LastRun) {
+		t.Errorf("changed task %s with %d runs, want %s with its 1 run", change.ID, change.Stats.Runs, before["change"].ID)
+	}
+}
+
+func TestReloadFromInvalid(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("keep").Frequently().Minutes(5).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		defs     []TaskDef
+		registry map[string]FuncToExec
+	}{
+		{"no-func", []TaskDef{{Name: "new", RunType: RunFrequently, Interval: time.Minute}}, nil},
+		{"no-name", []TaskDef{{RunType: RunFrequently, Interval: time.Minute}}, map[string]FuncToExec{"": func() {}}},
+		{"invalid", []TaskDef{{Name: "new", RunType: RunDaily, At: "25:00"}}, map[string]FuncToExec{"new": func() {}}},
+		{"duplicate", []TaskDef{
+			{Name: "new", RunType: RunFrequently, Interval: time.Minute},
+			{Name: "new", RunType: RunFrequently, Interval: time.Hour},
+		}, map[string]FuncToExec{"new": func() {}}},
+	}
+	for _, tt := range tests {
+		if err := ts.ReloadFrom(tt.defs, tt.registry); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+		if _, ok := ts.Get("keep"); !ok {
+			t.Errorf("%s: task list is changed by an invalid reload", tt.name)
+		}
+	}
+}
+
+func TestReloadFromInFlight(t *testing.T) {
+	ts := newTestScheduler()
+	started, release, finished := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	if _, err := ts.addTask(newTestTask("long").Frequently().Minutes(5).ExecFunc(func() {
+		close(started)
+		<-release
+		close(finished)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "long")
+	ts.runPending(time.Now())
+	<-started
+
+	defs := []TaskDef{{Name: "long", RunType: RunFrequently, Interval: 10 * time.Minute}}
+	if err := ts.ReloadFrom(defs, map[string]FuncToExec{"long": func() {}}); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("run in progress is interrupted by the reload")
+	}
+	ts.inFlight.Wait()
+	if info, _ := ts.Info("long"); info.Stats.Runs != 1 || info.Interval != 10*time.Minute {
+		t.Errorf("%d runs every %v after the reload, want the 1 run in progress every 10m", info.Stats.Runs, info.Interval)
+	}
+}
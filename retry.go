@@ -0,0 +1,62 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// retryRand is the random source of the retry delays, replace it with a seeded source for a repeatable sequence
+var (
+	retryRand   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	retryRandMu sync.Mutex
+)
+
+// RetryJitter method retries the 'ExecFuncErr' function up to the number of attempts when it returns an error,
+// each retry waits a random delay between 0 and min(cap, base*2^attempt), also known as full jitter.
+func (s *Tasks) RetryJitter(base, cap time.Duration, attempts int) *Tasks {
+	if base <= 0 || cap < base || attempts < 0 {
+		s.setErr("RetryJitter", fmt.Errorf("invalid retry jitter, base %v, cap %v, attempts %d", base, cap, attempts))
+		return s
+	}
+	s.retryBase = base
+	s.retryCap = cap
+	s.retryAttempts = attempts
+	return s
+}
+
+// retryDelay returns the full jitter delay before the retry attempt, the attempt starts from 0
+func (s *Tasks) retryDelay(attempt int) time.Duration {
+	ceiling := s.retryBase
+	for i := 0; i < attempt && ceiling < s.retryCap; i++ {
+		ceiling *= 2
+	}
+	if ceiling > s.retryCap {
+		ceiling = s.retryCap
+	}
+
+	retryRandMu.Lock()
+	defer retryRandMu.Unlock()
+	return time.Duration(retryRand.Int63n(int64(ceiling) + 1))
+}
+
+// callWithRetry calls the user's defined func and retries it based on the 'RetryJitter' settings,
+// a run directive returned by the func is never retried.
+func (s *Tasks) callWithRetry(call func() error) error {
+	err := s.checkSuccess(call())
+	var directive *RunDirective
+	for attempt := 0; err != nil && attempt < s.retryAttempts && !errors.As(err, &directive); attempt++ {
+		d := s.retryDelay(attempt)
+		msg := fmt.Sprintf("%s returns an error: %s, retry %d of %d in %v", s.Name, err.Error(), attempt+1, s.retryAttempts, d)
+		logger().Warnw(msg, s.logKV()...)
+		color.Yellow(msg)
+
+		time.Sleep(d)
+		err = s.checkSuccess(call())
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package isked
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// seedRetryRand replaces the random source of the retry delays until the end of the test
+func seedRetryRand(t *testing.T, seed int64) {
+	retryRandMu.Lock()
+	prev := retryRand
+	retryRand = rand.New(rand.NewSource(seed))
+	retryRandMu.Unlock()
+	t.Cleanup(func() {
+		retryRandMu.Lock()
+		retryRand = prev
+		retryRandMu.Unlock()
+	})
+}
+
+func TestRetryDelayBounds(t *testing.T) {
+	seedRetryRand(t, 1)
+	s := newTestTask("jitter").RetryJitter(10*time.Millisecond, 100*time.Millisecond, 8)
+	for attempt, ceiling := range []time.Duration{10, 20, 40, 80, 100, 100, 100, 100} {
+		ceiling *= time.Millisecond
+		for i := 0; i < 200; i++ {
+			if d := s.retryDelay(attempt); d < 0 || d > ceiling {
+				t.Fatalf("attempt %d: delay %v, want between 0 and %v", attempt, d, ceiling)
+			}
+		}
+	}
+}
+
+func TestRetryDelaySeeded(t *testing.T) {
+	s := newTestTask("jitter").RetryJitter(time.Millisecond, time.Second, 5)
+	delays := func() []time.Duration {
+		seedRetryRand(t, 42)
+		var d []time.Duration
+		for attempt := 0; attempt < 5; attempt++ {
+			d = append(d, s.retryDelay(attempt))
+		}
+		return d
+	}
+	first, second := delays(), delays()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("delays %v and %v differ with the same seed", first, second)
+		}
+	}
+}
+
+func TestRetryJitter(t *testing.T) {
+	seedRetryRand(t, 7)
+	calls := 0
+	s := newTestTask("flaky").RetryJitter(time.Millisecond, 5*time.Millisecond, 3).ExecFuncErr(func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("downstream busy")
+		}
+		return nil
+	})
+	if err := s.callWithRetry(s.ExecuteFuncErr); err != nil || calls != 3 {
+		t.Errorf("error %v after %d calls, want success on the third call", err, calls)
+	}
+
+	calls = 0
+	failing := newTestTask("down").RetryJitter(time.Millisecond, 2*time.Millisecond, 2)
+	err := failing.callWithRetry(func() error {
+		calls++
+		return errors.New("down")
+	})
+	if err == nil || calls != 3 {
+		t.Errorf("error %v after %d calls, want the error after 1 call and 2 retries", err, calls)
+	}
+
+	if _, err := newTestScheduler().addTask(newTestTask("bad").RetryJitter(0, time.Second, 1).ExecFuncErr(func() error { return nil })); !hasProblem(err, "RetryJitter") {
+		t.Errorf("error %v, want a RetryJitter problem", err)
+	}
+}
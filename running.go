@@ -0,0 +1,37 @@
+package isked
+
+import (
+	"sort"
+)
+
+// Running gets the names of the tasks sorted by name whose run is in progress, including the last run
+// of a task that has already been removed from the task list, e.g to decide when to shut down.
+func (t *TaskScheduler) Running() []string {
+	t.mu.RLock()
+	names := make([]string, 0, len(t.active))
+	for name := range t.active {
+		names = append(names, name)
+	}
+	t.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// startRun counts the run of the task as in progress, call the returned func once the run is done
+func (t *TaskScheduler) startRun(taskName string) func() {
+	t.mu.Lock()
+	if t.active == nil {
+		t.active = make(map[string]int)
+	}
+	t.active[taskName]++
+	t.mu.Unlock()
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.active[taskName]--; t.active[taskName] <= 0 {
+			delete(t.active, taskName)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package isked
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunning(t *testing.T) {
+	ts := newTestScheduler()
+	started, release := make(chan struct{}), make(chan struct{})
+	if _, err := ts.addTask(newTestTask("slow").Frequently().Minutes(1).ExecFunc(func() {
+		started <- struct{}{}
+		<-release
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.addTask(newTestTask("idle").Frequently().Hours(1).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if got := ts.Running(); len(got) != 0 {
+		t.Fatalf("running %v before any run, want none", got)
+	}
+
+	makeDue(t, ts, "slow")
+	ts.runPending(time.Now())
+	<-started
+	if got := ts.Running(); !reflect.DeepEqual(got, []string{"slow"}) {
+		t.Errorf("running %v during the slow run, want [slow]", got)
+	}
+
+	// A removed task stays in the list until its last run is done
+	ts.RemoveTask("slow")
+	if got := ts.Running(); !reflect.DeepEqual(got, []string{"slow"}) {
+		t.Errorf("running %v after removing the task, want its run in progress", got)
+	}
+	close(release)
+	ts.inFlight.Wait()
+	if got := ts.Running(); len(got) != 0 {
+		t.Errorf("running %v after the run, want none", got)
+	}
+}
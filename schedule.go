@@ -0,0 +1,117 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleOptions holds all the parameters of a task, use it with the 'Schedule'
+// method as an alternative to the chained methods.
+type ScheduleOptions struct {
+	Name        string         // task name, random if empty, a suffix is added if already in use
+	RunType     RunType        // options: onetime, frequently, daily, weekly, monthly
+	Interval    time.Duration  // use for frequently option only, whole seconds from 1 second to 366 days
+	Weekday     time.Weekday   // use for weekly option only
+	MonthDay    int            // use for monthly option only, 0 means the last day of the month
+	At          string         // use for daily, weekly and monthly options, 24-hour clock e.g "15:04"
+	OneTime     time.Time      // use for onetime option only, the DateTime to execute the task
+	Location    *time.Location // timezone of the 'At' time, defaults to the local time
+	ExecuteFunc FuncToExec     // user's defined func to be executed
+}
+
+// Schedule validates all the options at once and adds the task, it returns the final task name.
+func (t *TaskScheduler) Schedule(opts ScheduleOptions) (string, error) {
+	s, err := opts.newTask()
+	if err != nil {
+		return "", err
+	}
+	s.Name = t.uniqueName(opts.Name)
+	if _, err := t.addTask(s); err != nil {
+		return "", err
+	}
+	return s.Name, nil
+}
+
+// newTask validates the options and creates the task without its name
+func (opts ScheduleOptions) newTask() (*Tasks, error) {
+	if opts.ExecuteFunc == nil {
+		return nil, errors.New("missing function to execute")
+	}
+
+	s := &Tasks{
+		monthName: time.Now().Local().Month(),
+	}
+	switch opts.RunType {
+	case RunOneTime:
+		if opts.OneTime.IsZero() {
+			return nil, errors.New("missing onetime DateTime for the onetime run type")
+		}
+		s.OneTime(opts.OneTime.Unix())
+
+	case RunFrequently:
+		if opts.Interval < _minInterval || opts.Interval > _maxInterval || opts.Interval%time.Second != 0 {
+			return nil, fmt.Errorf("invalid frequently interval %v, use whole seconds from %v to %v", opts.Interval, _minInterval, _maxInterval)
+		}
+		s.Frequently().setInterval(opts.Interval)
+
+	case RunDaily, RunWeekly, RunMonthly:
+		if !isValidAt(opts.At) {
+			return nil, fmt.Errorf("invalid 'At' time %q, use the 24-hour format e.g 15:04", opts.At)
+		}
+		switch opts.RunType {
+		case RunDaily:
+			s.Daily()
+		case RunWeekly:
+			if opts.Weekday < time.Sunday || opts.Weekday > time.Saturday {
+				return nil, fmt.Errorf("invalid weekday %d", opts.Weekday)
+			}
+			s.Weekly()
+			s.dayName = opts.Weekday
+		case RunMonthly:
+			if opts.MonthDay < 0 || opts.MonthDay > 31 {
+				return nil, fmt.Errorf("invalid month day %d, use 1 to 31 or 0 for the last day", opts.MonthDay)
+			}
+			s.Monthly().Every(opts.MonthDay)
+		}
+		s.At(opts.At)
+
+	default:
+		return nil, fmt.Errorf("invalid run type %q", opts.RunType)
+	}
+
+	s.In(opts.Location).ExecFunc(opts.ExecuteFunc)
+	return s, nil
+}
+
+// setInterval replaces the frequently interval using the largest unit that fits the duration
+func (s *Tasks) setInterval(d time.Duration) *Tasks {
+	switch {
+	case d%time.Hour == 0:
+		s.FrequencyInterval, s.FrequencyValue = _hours, int(d/time.Hour)
+	case d%time.Minute == 0:
+		s.FrequencyInterval, s.FrequencyValue = _minutes, int(d/time.Minute)
+	default:
+		s.FrequencyInterval, s.FrequencyValue = _seconds, int(d/time.Second)
+	}
+	return s
+}
+
+// addInterval adds the number of units to the frequently interval, a non-positive number counts as 1 unit
+func (s *Tasks) addInterval(n int, unit time.Duration) *Tasks {
+	if n <= 0 {
+		n = 1
+	}
+	if n > int((_maxInterval-s.interval())/unit) {
+		// Keep it above the maximum without overflowing, it's reported by 'AddTask'
+		return s.setInterval(_maxInterval + time.Second)
+	}
+	return s.setInterval(s.interval() + time.Duration(n)*unit)
+}
+
+// isValidAt checks if the 'At' time is in the 24-hour format without seconds, e.g "15:04"
+func isValidAt(rt string) bool {
+	_, _, _, err := parseClockTime(rt)
+	return err == nil && strings.Count(rt, ":") == 1
+}
@@ -0,0 +1,81 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleEachRunType(t *testing.T) {
+	ts := newTestScheduler()
+	fn := func() {}
+	tests := []struct {
+		opts    ScheduleOptions
+		runType RunType
+	}{
+		{ScheduleOptions{Name: "onetime", RunType: RunOneTime, OneTime: time.Now().Add(time.Hour), ExecuteFunc: fn}, RunOneTime},
+		{ScheduleOptions{Name: "frequently", RunType: RunFrequently, Interval: 90 * time.Second, ExecuteFunc: fn}, RunFrequently},
+		{ScheduleOptions{Name: "daily", RunType: RunDaily, At: "09:30", ExecuteFunc: fn}, RunDaily},
+		{ScheduleOptions{Name: "weekly", RunType: RunWeekly, Weekday: time.Friday, At: "17:00", ExecuteFunc: fn}, RunWeekly},
+		{ScheduleOptions{Name: "monthly", RunType: RunMonthly, MonthDay: 15, At: "08:00", ExecuteFunc: fn}, RunMonthly},
+	}
+	for _, tt := range tests {
+		name, err := ts.Schedule(tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.opts.Name, err)
+		}
+		info, ok := ts.Info(name)
+		if !ok || info.RunType != tt.runType {
+			t.Errorf("%s: added as %q, want %q", name, info.RunType, tt.runType)
+		}
+	}
+
+	info, _ := ts.Info("frequently")
+	if info.Interval != 90*time.Second {
+		t.Errorf("frequently interval %v, want 1m30s", info.Interval)
+	}
+	weekly, _ := ts.Info("weekly")
+	if at := weekly.NextRun.In(time.Local); at.Weekday() != time.Friday || at.Hour() != 17 {
+		t.Errorf("weekly next run %v, want Friday 17:00", at)
+	}
+	monthly, _ := ts.Info("monthly")
+	if at := monthly.NextRun.In(time.Local); at.Day() != 15 || at.Hour() != 8 {
+		t.Errorf("monthly next run %v, want the 15th at 08:00", at)
+	}
+}
+
+func TestScheduleRejects(t *testing.T) {
+	ts := newTestScheduler()
+	fn := func() {}
+	for _, opts := range []ScheduleOptions{
+		{Name: "no-func", RunType: RunDaily, At: "09:00"},
+		{Name: "no-type", ExecuteFunc: fn},
+		{Name: "bad-interval", RunType: RunFrequently, Interval: 1500 * time.Millisecond, ExecuteFunc: fn},
+		{Name: "bad-at", RunType: RunDaily, At: "25:00", ExecuteFunc: fn},
+		{Name: "bad-weekday", RunType: RunWeekly, Weekday: 7, At: "09:00", ExecuteFunc: fn},
+		{Name: "bad-day", RunType: RunMonthly, MonthDay: 32, At: "09:00", ExecuteFunc: fn},
+		{Name: "no-onetime", RunType: RunOneTime, ExecuteFunc: fn},
+	} {
+		if _, err := ts.Schedule(opts); err == nil {
+			t.Errorf("%s: no error", opts.Name)
+		}
+	}
+	if n := len(ts.TaskList); n != 0 {
+		t.Errorf("%d invalid tasks are added", n)
+	}
+}
+
+func TestScheduleUniqueName(t *testing.T) {
+	ts := newTestScheduler()
+	opts := ScheduleOptions{Name: "report", RunType: RunDaily, At: "09:00", ExecuteFunc: func() {}}
+	first, err := ts.Schedule(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ts.Schedule(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != "report" || second == first {
+		t.Errorf("names %q and %q, want report and a new name", first, second)
+	}
+}
@@ -1,15 +1,18 @@
 package isked
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/google/uuid"
-	"github.com/itrepablik/itrlog"
 )
 
 // ChannelTS is the channel to be used during cancellation of all the tasks
@@ -17,6 +20,15 @@ import (
 // to get the latest values and reload the task scheduler.
 var ChannelTS = make(chan bool, 1)
 
+// ErrTaskNotFound is returned when the task name is not in the task list
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrTaskExists is returned when the task name is already in the task list
+var ErrTaskExists = errors.New("task already exists")
+
+// ErrNoFirstRun is returned when the task being added has no first run, e.g its 'NextAt' func returns the zero time
+var ErrNoFirstRun = errors.New("task has no first run")
+
 // Name this package as 'gawain' meaning task
 const (
 	_seconds        = "seconds"
@@ -36,32 +48,164 @@ const (
 	_monthly        = "monthly"
 	_timeFormat     = "1504"
 	_dateTimeFormat = "Jan 02 2006 03:04:05 PM"
+	_minInterval    = time.Second          // smallest frequently interval
+	_maxInterval    = 366 * 24 * time.Hour // largest frequently interval
+)
+
+// RunType is the run type option of each task
+type RunType string
+
+// List of the run type options
+const (
+	RunOneTime    RunType = _onetime
+	RunFrequently RunType = _frequently
+	RunDaily      RunType = _daily
+	RunWeekly     RunType = _weekly
+	RunMonthly    RunType = _monthly
 )
 
 // FuncToExec is the function that needs to be executed as parameter
 type FuncToExec func()
 
+// FuncToExecErr is the function that needs to be executed as parameter which reports an error
+type FuncToExecErr func() error
+
+// FuncToExecShard is the function that needs to be executed as parameter which gets its shard and the total number of shards
+type FuncToExecShard func(shard, total int)
+
+// FuncToExecCtx is the function that needs to be executed as parameter which gets a context and reports an error
+type FuncToExecCtx func(ctx context.Context) error
+
+// FuncToExecMeta is the function that needs to be executed as parameter which gets the information of its run and reports an error
+type FuncToExecMeta func(meta RunMeta) error
+
+// RunMeta is the information of a run given to the 'ExecFuncMeta' function
+type RunMeta struct {
+	Name      string
+	ID        string
+	Scheduled time.Time // scheduled DateTime of the run
+	Started   time.Time // actual start of the run
+}
+
 // TaskScheduler is the task scheduler's format
 type TaskScheduler struct {
-	TaskList map[string][]Tasks
-	mu       sync.Mutex
+	TaskList          map[string][]Tasks
+	mu                sync.RWMutex  // writers only hold it while touching the task list, never while computing schedules
+	conflictTolerance time.Duration // how close the next runs can be to be reported as conflicts
+	gracePeriod       time.Duration // how early a task is considered due before its next run
+	batchWindow       time.Duration // how far ahead the tasks are picked up together with the due tasks
+	logInterval       time.Duration // minimum time between the "next schedule" messages of each task
+	runWatchdog       time.Duration // how long a run can take before it's logged as stuck
+	releaseStuck      bool          // true, if the stuck runs are no longer counted as in progress
+	errorLogWindow    time.Duration // how long the repeated errors of each task are collapsed into a count
+	maxTasks          int           // maximum number of tasks in the task list, 0 means no limit
+	wake              chan struct{} // signals the running loop that the task list has changed
+	wakeOnce          sync.Once
+	halt              chan error // signals the running loop to stop because of a critical task failure
+	haltOnce          sync.Once
+	running           int32          // 1 while a running loop is active, accessed atomically
+	maintenance       int32          // 1 while the maintenance mode is on, accessed atomically
+	maintenanceCheck  func() bool    // the maintenance mode is also on while it returns true
+	defaultLoc        *time.Location // timezone of the tasks that don't use the 'In' method, nil for the local time
+	weekStart         time.Weekday   // first day of the week to number the weeks of the month, Sunday by default
+	closed            int32          // 1 once the task scheduler is closed, accessed atomically
+	inFlight          sync.WaitGroup // runs that are in progress
+	active            map[string]int // runs in progress of each task name, including the removed tasks
+	panicPolicy       PanicPolicy    // what to do when the user's defined func of a task panics
+	loopMu            sync.Mutex
+	loop              LoopStats                 // timing of the running loop itself
+	loopStarted       time.Time                 // zero if the running loop is not active
+	executions        int64                     // completed runs of all the tasks including the namespaces
+	parent            *TaskScheduler            // the task scheduler that runs the tasks of this namespace
+	namespaces        map[string]*TaskScheduler // sub-schedulers sharing the loop of this task scheduler
+	runsCtx           context.Context           // parent of the contexts of the runs, cancelled by 'Shutdown'
+	cancelRuns        context.CancelFunc
+	watchMu           sync.Mutex
+	watchers          []chan ScheduleChange // channels returned by 'Watch'
+	droppedChanges    int                   // changes dropped from the full watch channels
+	onDropped         func(count int)       // called with the number of changes dropped so far
 }
 
 // Tasks is the individual task item to be executed
 type Tasks struct {
 	Name                   string
-	RunType                string       // options: onetime, frequently, daily, weekly, monthly
-	FrequencyInterval      string       // use for frequently option only: seconds, minutes, hours
-	FrequencyValue         int          // use for frequently option only, minimum value of 1, e.g 1 second
-	ExecuteFunc            FuncToExec   // user's defined func to be executed
-	runAtHour, runAtMinute string       // 24-hour clock beginning at midnight (0000 hours) and ends at 2359 hours
-	isRunAt                bool         // true, if use the '.At("15:04")' method, for frequently it's not applicable
-	dayName                time.Weekday // internal usage: dayName such as 'Monday' using time.Weekday format
-	monthName              time.Month   // internal usage: monthName such as 'January' using time.Month format
-	monthDay               int          // internal usage: monthDay is serve as the specific day of the month
-	nextRunTime            int64        // internal usage: next scheduled run
-	lastRunTime            int64        // internal usage: last executed task
-	created                int64        // internal usage: task created
+	RunType                string               // options: onetime, frequently, daily, weekly, monthly
+	FrequencyInterval      string               // use for frequently option only: seconds, minutes, hours
+	FrequencyValue         int                  // use for frequently option only, minimum value of 1, e.g 1 second
+	ExecuteFunc            FuncToExec           // user's defined func to be executed
+	ExecuteFuncErr         FuncToExecErr        // user's defined func to be executed that returns an error
+	ExecuteFuncCtx         FuncToExecCtx        // user's defined func to be executed that gets a context and returns an error
+	ExecuteFuncShard       FuncToExecShard      // user's defined func to be executed for each shard
+	ExecuteFuncMeta        FuncToExecMeta       // user's defined func to be executed that gets the information of its run and returns an error
+	runAtHour, runAtMinute string               // 24-hour clock beginning at midnight (0000 hours) and ends at 2359 hours
+	isRunAt                bool                 // true, if use the '.At("15:04")' method, for frequently it's not applicable
+	dayName                time.Weekday         // internal usage: dayName such as 'Monday' using time.Weekday format
+	monthName              time.Month           // internal usage: monthName such as 'January' using time.Month format
+	monthDay               int                  // internal usage: monthDay is serve as the specific day of the month
+	loc                    *time.Location       // internal usage: timezone of the 'At' time, defaults to the local time
+	defaultLoc             *time.Location       // internal usage: default timezone of the task scheduler, used if 'loc' is not set
+	weekOfMonth            int                  // internal usage: the week of the month of the weekly option, 0 for every week
+	weekStart              time.Weekday         // internal usage: first day of the week of the task scheduler to number the weeks
+	untilSuccess           bool                 // internal usage: true, if the task is removed after the first successful run
+	critical               bool                 // internal usage: true, if an error of the task stops the task scheduler
+	immediate              bool                 // internal usage: true, if the task runs right away when added
+	runAtStartup           bool                 // internal usage: true, if the task runs right away unless it already ran before it's restored
+	fixedRate              bool                 // internal usage: true, if the next run is computed from the scheduled run
+	phase                  time.Duration        // internal usage: offset of the frequently runs within the interval
+	hasPhase               bool                 // internal usage: true, if use the '.Phase(d)' method
+	epochAligned           bool                 // internal usage: true, if use the '.EpochAligned()' method
+	requestedDay           int                  // internal usage: the day of the 'Every' method before it's clamped
+	strictMonthDay         bool                 // internal usage: true, if the day of the monthly option is never clamped
+	waitFor                <-chan struct{}      // internal usage: the task doesn't run until the channel fires
+	gateDone               chan struct{}        // internal usage: closed once the task is removed so it stops waiting for its channel
+	deadlineAtNextRun      bool                 // internal usage: true, if the context of the run is cancelled at the next run
+	minGap                 time.Duration        // internal usage: minimum time between the end of a run and the next run
+	debounce               time.Duration        // internal usage: quiet time after the last trigger before the task runs
+	shards                 int                  // internal usage: number of parallel calls of the 'ExecFuncShard' function
+	then                   []FuncToExecErr      // internal usage: functions executed in order after the user's defined func
+	success                func(err error) bool // internal usage: user's defined func to decide if the run is successful
+	onEnd                  func(reason string)  // internal usage: user's defined func to be called once the task has ended
+	endReason              string               // internal usage: why the task has ended, empty while it's in the task list
+	running                int                  // internal usage: number of runs in progress, tracked with the 'MinGap' method only
+	released               int                  // internal usage: number of stuck runs that are no longer counted as in progress
+	lastRunEnd             time.Time            // internal usage: the time the last run has finished
+	lastErr                error                // internal usage: the error of the last run, nil if it succeeded
+	lastErrAt              time.Time            // internal usage: the time the last error happened
+	errLogMsg              string               // internal usage: the last error message that was logged
+	errLogAt               time.Time            // internal usage: the time the last error message was logged
+	errRepeats             int                  // internal usage: number of times the last logged error is repeated since
+	firstRunAt             time.Time            // internal usage: the first scheduled run after the immediate run
+	adaptive               intervalFunc         // internal usage: gets the next interval after each run
+	missedBy               time.Duration        // internal usage: how late a run can start before it's alerted
+	missedAlert            alertFunc            // internal usage: user's defined func to be called when a run is late
+	limit                  int                  // internal usage: maximum number of runs, 0 means no limit
+	runCount               int                  // internal usage: number of runs so far
+	startingFrom           time.Time            // internal usage: the task doesn't run before this DateTime
+	until                  time.Time            // internal usage: the task is removed once its next run is after this DateTime
+	paused                 bool                 // internal usage: true, while the task is held by the 'Pause' method
+	problems               []FieldError         // internal usage: the problems found while setting up the task
+	stats                  TaskStats            // internal usage: execution statistics
+	hasBlackout            bool                 // internal usage: true, if use the '.Blackout(start, end)' method
+	skipBlackout           bool                 // internal usage: true, if the run inside the blackout window is skipped
+	blackoutStart          int                  // internal usage: start of the blackout window in seconds since midnight
+	blackoutEnd            int                  // internal usage: end of the blackout window in seconds since midnight
+	nextFunc               func() time.Duration // internal usage: user's defined func to compute the wait before the next run
+	nextAt                 func() time.Time     // internal usage: user's defined func to compute the DateTime of the next run
+	backfill               int                  // internal usage: maximum number of missed runs to catch up on
+	pendingBackfill        int                  // internal usage: number of missed runs left to catch up on
+	onDates                bool                 // internal usage: true, if use the '.OnDates(times...)' method
+	dates                  []time.Time          // internal usage: the next DateTime to run on after the next run
+	isNextWeekday          bool                 // internal usage: true, if use the '.NextWeekday(d)' method
+	lastLogged             time.Time            // internal usage: last time the "next schedule" message was logged
+	compensate             bool                 // internal usage: true, if the missed intervals are executed when the task is picked up late
+	logFields              []interface{}        // internal usage: key-value pairs added to every log of the task
+	id                     string               // internal usage: immutable task ID assigned when the task is added
+	retryBase              time.Duration        // internal usage: base delay of the 'RetryJitter' method
+	retryCap               time.Duration        // internal usage: maximum delay of the 'RetryJitter' method
+	retryAttempts          int                  // internal usage: maximum number of retries of the 'RetryJitter' method
+	nextRunTime            time.Time            // internal usage: next scheduled run
+	lastRunTime            time.Time            // internal usage: last executed task
+	created                time.Time            // internal usage: task created
 }
 
 // TS initialize the 'TaskScheduler' struct with an empty values
@@ -104,37 +248,22 @@ func SetLogDT(dtFormat string) *LogDTFormat {
 	return dt
 }
 
-// Seconds is the naming convention for the Frequently method as 'seconds' option
+// Seconds is the naming convention for the Frequently method as 'seconds' option, the chained units
+// are combined, e.g '.Hours(1).Minutes(30)' is the same as '.Minutes(90)'
 func (s *Tasks) Seconds(interval int) *Tasks {
-	s.FrequencyInterval = _seconds
-	if interval <= 0 {
-		s.FrequencyValue = 1 // Default to 1 sec
-	} else {
-		s.FrequencyValue = interval
-	}
-	return s
+	return s.addInterval(interval, time.Second)
 }
 
-// Minutes is the naming convention for the Frequently method as 'minutes' option
+// Minutes is the naming convention for the Frequently method as 'minutes' option, the chained units
+// are combined, e.g '.Hours(1).Minutes(30)' is the same as '.Minutes(90)'
 func (s *Tasks) Minutes(interval int) *Tasks {
-	s.FrequencyInterval = _minutes
-	if interval <= 0 {
-		s.FrequencyValue = 1 // Default to 1 sec
-	} else {
-		s.FrequencyValue = interval
-	}
-	return s
+	return s.addInterval(interval, time.Minute)
 }
 
-// Hours is the naming convention for the Frequently method as 'hours' option
+// Hours is the naming convention for the Frequently method as 'hours' option, the chained units
+// are combined, e.g '.Hours(1).Minutes(30)' is the same as '.Minutes(90)'
 func (s *Tasks) Hours(interval int) *Tasks {
-	s.FrequencyInterval = _hours
-	if interval <= 0 {
-		s.FrequencyValue = 1 // Default to 1 sec
-	} else {
-		s.FrequencyValue = interval
-	}
-	return s
+	return s.addInterval(interval, time.Hour)
 }
 
 // Monday is the naming convention for the day called 'Monday' method
@@ -181,6 +310,7 @@ func (s *Tasks) Sunday() *Tasks {
 
 // Every is use mainly for the 'Monthly' method that serve as the specific day of each month
 func (s *Tasks) Every(day int) *Tasks {
+	s.requestedDay = day
 	today := time.Now()
 	lastDayOfMonth := getLastDayOfMonth(day, today.Month())
 	switch {
@@ -194,28 +324,65 @@ func (s *Tasks) Every(day int) *Tasks {
 	return s
 }
 
-// Get gets the specific task information using the task name
+// Get gets a copy of the specific task information using the task name or the task ID, the copy is not
+// changed by the running loop
 func (t *TaskScheduler) Get(taskName string) ([]Tasks, bool) {
-	taskData, ok := t.TaskList[taskName]
-	if !ok {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	taskData, ok := t.lookup(taskName)
+	return append([]Tasks(nil), taskData...), ok
+}
+
+// lookup finds the task using the task name or the task ID, the lock of the task list must be held
+func (t *TaskScheduler) lookup(key string) ([]Tasks, bool) {
+	if taskData, ok := t.TaskList[key]; ok {
 		return taskData, ok
 	}
-	return taskData, ok
+	for _, e := range t.TaskList {
+		if len(e) > 0 && e[0].id == key {
+			return e, true
+		}
+	}
+	return nil, false
 }
 
-// TaskName method is the run type option of each task that execute once only
-func TaskName(taskName string) *Tasks {
+// SetExecFunc replaces the function to be executed of an existing task using the task name or the task ID
+// without changing its schedule, any run that is already in progress keeps the old function.
+func (t *TaskScheduler) SetExecFunc(taskName string, fn FuncToExec) error {
+	if t.isClosed() {
+		return ErrSchedulerClosed
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		return fmt.Errorf("%s: %w", taskName, ErrTaskNotFound)
+	}
+	taskData[0].clearFuncs()
+	taskData[0].ExecuteFunc = fn
+	return nil
+}
+
+// uniqueName returns the task name that is not yet used in the task list
+func (t *TaskScheduler) uniqueName(taskName string) string {
 	newTaskName := strings.TrimSpace(taskName)
 	if len(newTaskName) == 0 {
-		taskName = uuid.New().String() // Assign with random strings if empty
+		newTaskName = uuid.New().String() // Assign with random strings if empty
 	}
 	// Check if any duplicate task name, add extra timestamp using unix format
-	payLoad, _ := TS.Get(taskName)
+	t.mu.RLock()
+	payLoad := t.TaskList[newTaskName]
+	t.mu.RUnlock()
 	for _, e := range payLoad {
 		newTaskName = e.Name + "_" + fmt.Sprintf("%v", time.Now().Unix())
 	}
+	return newTaskName
+}
+
+// TaskName method is the run type option of each task that execute once only
+func TaskName(taskName string) *Tasks {
 	TK = Tasks{
-		Name:              newTaskName,
+		Name:              TS.uniqueName(taskName),
 		RunType:           "",
 		FrequencyInterval: "",
 		FrequencyValue:    0,
@@ -225,9 +392,9 @@ func TaskName(taskName string) *Tasks {
 		monthDay:          0,
 		monthName:         time.Now().Local().Month(),
 		isRunAt:           false,
-		nextRunTime:       0,
-		lastRunTime:       0,
-		created:           time.Now().Unix(),
+		nextRunTime:       time.Time{},
+		lastRunTime:       time.Time{},
+		created:           time.Now(),
 	}
 	return &TK
 }
@@ -246,13 +413,76 @@ func (s *Tasks) OneTime(dt int64) *Tasks {
 
 	if dt < timeNow {
 		// Set the default DateTime of +24 hours from the current time if entered time is not a future time.
-		s.nextRunTime = time.Now().Add(24 * time.Hour).Unix()
+		s.nextRunTime = time.Now().Add(24 * time.Hour)
 	} else {
-		s.nextRunTime = dt
+		s.nextRunTime = time.Unix(dt, 0)
 	}
 	return s
 }
 
+// OnDates method is the onetime run type that executes once on each of the future DateTime in order,
+// the task is removed after its last run. The past DateTime are ignored.
+func (s *Tasks) OnDates(times ...time.Time) *Tasks {
+	s.RunType = _onetime
+	s.onDates = true
+	now := time.Now()
+	var dates []time.Time
+	for _, e := range times {
+		if e.After(now) {
+			dates = append(dates, e)
+		}
+	}
+	if len(dates) == 0 {
+		s.setErr("OnDates", errors.New("no future DateTime to run on"))
+		return s
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	s.nextRunTime = dates[0]
+	s.dates = dates[1:]
+	return s
+}
+
+// NextWeekday method is the onetime run type that executes once on the next occurrence of the weekday,
+// use it with the 'At' method, e.g '.NextWeekday(time.Friday).At("17:00")'. It's today if today is
+// the same weekday and the 'At' time has not passed yet.
+func (s *Tasks) NextWeekday(d time.Weekday) *Tasks {
+	s.RunType = _onetime
+	s.isNextWeekday = true
+	s.dayName = d
+	return s
+}
+
+// nextWeekdayRun computes the next occurrence of the weekday at the 'At' time after the 'now' time
+func (s *Tasks) nextWeekdayRun(now time.Time) time.Time {
+	runHour, _ := strconv.Atoi(s.runAtHour)
+	runMinute, _ := strconv.Atoi(s.runAtMinute)
+	today := now.In(s.location())
+	days := (int(s.dayName) - int(today.Weekday()) + 7) % 7
+
+	nextRun := time.Date(today.Year(), today.Month(), today.Day()+days, runHour, runMinute, 0, 0, today.Location())
+	if !nextRun.After(now) {
+		nextRun = nextRun.AddDate(0, 0, 7)
+	}
+	return nextRun
+}
+
+// Compensate method is for frequently option, when the task is picked up late by one or more
+// intervals, the func is executed once for each missed interval on top of the due run.
+// By default the task runs just once no matter how late it is.
+func (s *Tasks) Compensate() *Tasks {
+	s.compensate = true
+	return s
+}
+
+// compensatedRuns returns how many times the due task needs to be executed at the 'now' time
+func (s *Tasks) compensatedRuns(now time.Time) int {
+	interval := s.interval()
+	if !s.compensate || s.RunType != _frequently || interval <= 0 || !now.After(s.nextRunTime) {
+		return 1
+	}
+	return 1 + int(now.Sub(s.nextRunTime)/interval)
+}
+
 // Daily method is the run type option of each task that execute every day
 func (s *Tasks) Daily() *Tasks {
 	s.RunType = _daily
@@ -271,247 +501,1170 @@ func (s *Tasks) Monthly() *Tasks {
 	return s
 }
 
-// At method is when to start executing the task with DateTime in unix time format
+// At method is when to start executing the task with DateTime in unix time format, the time must use
+// the 24-hour format e.g "15:04", otherwise it's reported by 'AddTask'
 func (s *Tasks) At(rt string) *Tasks {
 	// Only allowed 'At' method can use this process
 	// OneTime and Frequently is not required
-	if s.RunType != _onetime && s.RunType != _frequently {
-		s.isRunAt = true
+	if (s.RunType != _onetime || s.isNextWeekday) && s.RunType != _frequently {
 		// Check with the correct 24-hour format
-		pTime := strings.TrimSpace(rt)
-		if strings.Contains(pTime, ":") {
-			if len(pTime) == 5 {
-				s.runAtHour = strings.Split(pTime, ":")[0]
-				s.runAtMinute = strings.Split(pTime, ":")[1]
-			} else {
-				s.runAtHour = "00"
-				s.runAtMinute = "00" // Default to 12-midnight
-			}
-		} else {
-			s.runAtHour = "00"
-			s.runAtMinute = "00" // Default to 12-midnight
+		if !isValidAt(rt) {
+			s.setErr("At", fmt.Errorf("invalid 'At' time %q, use the 24-hour format e.g 15:04", rt))
+			return s
 		}
+		s.isRunAt = true
+		hour, minute, _, _ := parseClockTime(rt)
+		s.runAtHour = fmt.Sprintf("%02d", hour)
+		s.runAtMinute = fmt.Sprintf("%02d", minute)
+	}
+	return s
+}
+
+// Limit method sets the maximum number of runs, the task is removed after its last run
+func (s *Tasks) Limit(runs int) *Tasks {
+	if runs < 0 {
+		runs = 0 // No limit
+	}
+	s.limit = runs
+	return s
+}
+
+// StartingFrom method sets the DateTime of when the task is allowed to start running,
+// for frequently option, the first run is exactly at this DateTime.
+func (s *Tasks) StartingFrom(dt time.Time) *Tasks {
+	s.startingFrom = dt
+	return s
+}
+
+// Between method runs the recurring task only within the date range, its first run is like the
+// 'StartingFrom' method and it's removed once its next run is after the end, e.g for the seasonal tasks.
+func (s *Tasks) Between(start, end time.Time) *Tasks {
+	if !start.Before(end) {
+		s.setErr("Between", fmt.Errorf("invalid date range, the start %v must be before the end %v", start, end))
+		return s
+	}
+	s.StartingFrom(start)
+	s.until = end
+	return s
+}
+
+// In method sets the timezone to be used for the 'At' time, default is the local time
+func (s *Tasks) In(loc *time.Location) *Tasks {
+	s.loc = loc
+	return s
+}
+
+// location returns the timezone of the task
+func (s *Tasks) location() *time.Location {
+	switch {
+	case s.loc != nil:
+		return s.loc
+	case s.defaultLoc != nil:
+		return s.defaultLoc
+	}
+	return time.Local
+}
+
+// validate checks the task before it's added to the task list, it returns the 'ValidationError'
+// with all the problems found
+func (s *Tasks) validate() error {
+	problems := append([]FieldError(nil), s.problems...)
+	add := func(field string, err error) {
+		problems = append(problems, FieldError{Field: field, Err: err})
+	}
+	if len(s.Name) == 0 {
+		add("Name", errors.New("missing task name, e.g use the 'New' method of the template"))
+	}
+	switch s.RunType {
+	case _onetime, _frequently, _daily, _weekly, _monthly:
+	case "":
+		add("RunType", errors.New("missing run type"))
+	default:
+		add("RunType", fmt.Errorf("invalid run type %q", s.RunType))
 	}
+	if !s.hasFunc() {
+		add("ExecFunc", errors.New("missing function to execute"))
+	}
+	// Schedules are computed in whole seconds, anything below it would never run as expected
+	if s.RunType == _frequently && s.nextFunc == nil && s.interval() < _minInterval {
+		add("Interval", fmt.Errorf("frequently interval %v is below the minimum of %v", s.interval(), _minInterval))
+	}
+	if s.RunType == _frequently && s.nextFunc == nil && s.interval() > _maxInterval {
+		add("Interval", fmt.Errorf("frequently interval %v is above the maximum of %v", s.interval(), _maxInterval))
+	}
+	if s.hasPhase && (s.RunType != _frequently || s.nextFunc != nil || s.phase < 0 || s.phase >= s.interval()) {
+		add("Phase", fmt.Errorf("invalid phase %v, use it with the frequently option below the interval of %v", s.phase, s.interval()))
+	}
+	if s.epochAligned && (s.RunType != _frequently || s.nextFunc != nil) {
+		add("EpochAligned", errors.New("epoch alignment is for the frequently option only"))
+	}
+	if s.weekOfMonth > 0 && (s.RunType != _weekly || s.nextFunc != nil) {
+		add("InWeek", errors.New("week of the month is for the weekly option only"))
+	}
+	if err := s.strictMonthDayError(time.Now()); err != nil {
+		add("Every", err)
+	}
+	if !s.until.IsZero() && !s.until.After(time.Now()) {
+		add("Between", fmt.Errorf("date range already ended on %v", s.until))
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// setErr keeps the problem of the setting found while setting up the task, it's reported by 'AddTask'
+func (s *Tasks) setErr(field string, err error) {
+	s.problems = append(s.problems, FieldError{Field: field, Err: err})
+}
+
+// LogFields method attaches the key-value pairs to every log of the task, e.g '.LogFields("tenant", "acme")'
+func (s *Tasks) LogFields(kv ...interface{}) *Tasks {
+	s.logFields = append(s.logFields, kv...)
 	return s
 }
 
+// logKV returns the key-value pairs for each log of the task
+func (s *Tasks) logKV() []interface{} {
+	kv := make([]interface{}, 0, len(s.logFields)+2)
+	kv = append(kv, "log_time", time.Now().Format(logDateTimeFormat))
+	return append(kv, s.logFields...)
+}
+
 // ExecFunc method collect the function as parameter that needs to be executed
 func (s *Tasks) ExecFunc(fn FuncToExec) *Tasks {
+	s.clearFuncs()
 	s.ExecuteFunc = fn
 	return s
 }
 
-// AddTask create individual task to be executed
-func (s *Tasks) AddTask() {
-	var nextSchedToRun int64 = 0
+// ExecFuncErr method collect the function that returns an error as parameter that needs to be executed
+func (s *Tasks) ExecFuncErr(fn FuncToExecErr) *Tasks {
+	s.clearFuncs()
+	s.ExecuteFuncErr = fn
+	return s
+}
+
+// ExecFuncCtx method collect the function that gets a context and returns an error as parameter that needs
+// to be executed, it's treated like the 'ExecFuncErr' function.
+func (s *Tasks) ExecFuncCtx(fn FuncToExecCtx) *Tasks {
+	s.clearFuncs()
+	s.ExecuteFuncCtx = fn
+	return s
+}
+
+// ExecFuncMeta method collect the function that gets the information of its run and returns an error as parameter
+// that needs to be executed, it's treated like the 'ExecFuncErr' function.
+func (s *Tasks) ExecFuncMeta(fn FuncToExecMeta) *Tasks {
+	s.clearFuncs()
+	s.ExecuteFuncMeta = fn
+	return s
+}
+
+// ExecFuncShard method collect the function that gets its shard as parameter that needs to be executed,
+// use it with the 'Shards' method.
+func (s *Tasks) ExecFuncShard(fn FuncToExecShard) *Tasks {
+	s.clearFuncs()
+	s.ExecuteFuncShard = fn
+	return s
+}
+
+// Shards method runs the 'ExecFuncShard' function n times in parallel on each run, each one gets its
+// shard from 0 to n-1 and the total number of shards. The run is done once all the shards are done.
+func (s *Tasks) Shards(n int) *Tasks {
+	if n <= 0 {
+		s.setErr("Shards", fmt.Errorf("invalid number of shards %d", n))
+		return s
+	}
+	s.shards = n
+	return s
+}
+
+// runShards calls the 'ExecFuncShard' function for all the shards in parallel and waits for them,
+// it returns the error of the first shard that panics
+func (t *TaskScheduler) runShards(s *Tasks) error {
+	total := s.shards
+	if total <= 0 {
+		total = 1
+	}
+	errs := make([]error, total)
+	var wg sync.WaitGroup
+	wg.Add(total)
+	for i := 0; i < total; i++ {
+		go func(shard int) {
+			defer wg.Done()
+			errs[shard] = t.guard(s, func() error {
+				s.ExecuteFuncShard(shard, total)
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Then method adds the function to be executed after the previous one on each run, e.g
+// '.ExecFunc(a).Then(b).Then(c)' runs a, b then c in order.
+func (s *Tasks) Then(fn FuncToExec) *Tasks {
+	return s.ThenErr(func() error {
+		fn()
+		return nil
+	})
+}
+
+// ThenErr method adds the function that returns an error to be executed after the previous one on each run,
+// the functions after it are not executed if it returns an error, the same goes for the 'ExecFuncErr' function.
+func (s *Tasks) ThenErr(fn FuncToExecErr) *Tasks {
+	s.then = append(s.then, fn)
+	return s
+}
+
+// clearFuncs removes the user's defined funcs, a task only has one of them
+func (s *Tasks) clearFuncs() {
+	s.ExecuteFunc = nil
+	s.ExecuteFuncErr = nil
+	s.ExecuteFuncCtx = nil
+	s.ExecuteFuncShard = nil
+	s.ExecuteFuncMeta = nil
+}
+
+// hasFunc checks if the task has a function to be executed
+func (s *Tasks) hasFunc() bool {
+	return s.ExecuteFunc != nil || s.ExecuteFuncErr != nil || s.ExecuteFuncCtx != nil || s.ExecuteFuncShard != nil || s.ExecuteFuncMeta != nil
+}
+
+// DeadlineAtNextRun method cancels the context of the 'ExecFuncCtx' function once the next run is due,
+// so a run never overlaps the next one. It has no effect if there's no next run.
+func (s *Tasks) DeadlineAtNextRun() *Tasks {
+	s.deadlineAtNextRun = true
+	return s
+}
+
+// UntilSuccess method keeps running the task on its schedule until the 'ExecFuncErr' function
+// returns a nil error, then the task is removed from the task list.
+func (s *Tasks) UntilSuccess() *Tasks {
+	s.untilSuccess = true
+	return s
+}
+
+// Immediately method runs the task right away when added, then it follows its schedule as if the
+// immediate run didn't happen, e.g '.Daily().At("09:00").Immediately()' runs now then every day at 09:00.
+func (s *Tasks) Immediately() *Tasks {
+	s.immediate = true
+	return s
+}
+
+// RunAtStartup method runs the task right away when added like the 'Immediately' method, but it's kept in its
+// definition, so a task restored with 'Import' only runs right away if it never ran before, e.g the process
+// restarted before the startup run.
+func (s *Tasks) RunAtStartup() *Tasks {
+	s.runAtStartup = true
+	return s
+}
+
+// FixedRate method schedules the next run of the frequently task from its scheduled run instead of the
+// time it's picked up, so the runs don't drift with the loop latency. Missed runs are skipped if it's
+// behind by more than the interval.
+func (s *Tasks) FixedRate() *Tasks {
+	s.fixedRate = true
+	return s
+}
+
+// nextFixedRate returns the first run after the 'now' time that is aligned to the scheduled run
+func (s *Tasks) nextFixedRate(now time.Time) time.Time {
+	interval := s.interval()
+	next := s.nextRunTime.Add(interval)
+	if !next.After(now) {
+		missed := now.Sub(next)/interval + 1
+		next = next.Add(missed * interval)
+	}
+	return next
+}
+
+// Critical method stops the task scheduler when the 'ExecFuncErr' function returns an error, use it for
+// the tasks that the other tasks can't run without, e.g a license check.
+func (s *Tasks) Critical() *Tasks {
+	s.critical = true
+	return s
+}
+
+// AddTask create individual task to be executed, it returns the error if the task is not added, e.g a missing
+// run type or an invalid 'At' time. Use 'Add' to also get the first scheduled run.
+func (s *Tasks) AddTask() error {
+	_, err := TS.addTask(s)
+	return err
+}
+
+// Add method adds the task like 'AddTask' and returns its first scheduled run, e.g to confirm
+// "scheduled for <time>", or the error if the task is not added.
+func (s *Tasks) Add() (time.Time, error) {
+	return TS.addTask(s)
+}
+
+// AddTask adds the task to this task scheduler instead of the default one like 'Add', e.g to a namespace:
+//
+//	isked.TS.Namespace("billing").AddTask(isked.TaskName("Invoice").Daily().At("09:00").ExecFunc(myFunc1))
+func (t *TaskScheduler) AddTask(s *Tasks) (time.Time, error) {
+	return t.addTask(s)
+}
+
+// addTask stores the task to the task list with its first scheduled run and returns it
+func (t *TaskScheduler) addTask(s *Tasks) (time.Time, error) {
+	if t.isClosed() {
+		return time.Time{}, ErrSchedulerClosed
+	}
+	if err := s.validate(); err != nil {
+		msg := s.Name + " is not added: " + err.Error()
+		logger().Errorw(msg, s.logKV()...)
+		color.Red(msg)
+		return time.Time{}, fmt.Errorf("%s: %w", s.Name, err)
+	}
+
+	newTask := *s
+	newTask.defaultLoc = t.defaultLocation()
+	newTask.weekStart = t.weekStartDay()
+	if err := newTask.setFirstRun(newTask.initialRun(), time.Now()); err != nil {
+		msg := s.Name + " is not added: " + err.Error()
+		logger().Errorw(msg, s.logKV()...)
+		color.Red(msg)
+		return time.Time{}, fmt.Errorf("%s: %w", s.Name, err)
+	}
+	if err := t.storeTask(newTask); err != nil {
+		return time.Time{}, err
+	}
+	return newTask.nextRunTime, nil
+}
+
+// setFirstRun sets the first scheduled run of the task that is about to be added, it returns an error
+// if there's no first run or it's outside its date range
+func (s *Tasks) setFirstRun(first, now time.Time) error {
+	if first.IsZero() {
+		return ErrNoFirstRun
+	}
+	if !s.until.IsZero() && first.After(s.until) {
+		return errors.New("first run is after the end of its date range")
+	}
+	s.nextRunTime = first
+	if s.immediate || s.runAtStartup {
+		s.firstRunAt = first
+		s.nextRunTime = now
+	}
+	s.lastRunTime = time.Time{}
+	s.created = now
+	return nil
+}
+
+// initialRun returns the first scheduled run of the task that is about to be added
+func (s *Tasks) initialRun() time.Time {
+	switch {
+	case s.RunType == _onetime && s.isNextWeekday:
+		return s.nextWeekdayRun(time.Now())
+	case s.RunType == _onetime:
+		return s.nextRunTime
+	case s.startingFrom.After(time.Now()):
+		return s.firstRunFrom(s.startingFrom)
+	case !s.startingFrom.IsZero() && s.prepareBackfill(s.firstRunFrom(s.startingFrom), time.Now()):
+		return s.nextRunTime
+	default:
+		return s.firstRun(time.Now())
+	}
+}
+
+// firstRun returns the first run of the newly added task, the daily and weekly options run right away
+// if the task is added within the 'At' minute, e.g at 14:18:30 for "14:18", instead of the next day or week.
+func (s *Tasks) firstRun(now time.Time) time.Time {
+	if (s.RunType == _daily || s.RunType == _weekly) && s.nextFunc == nil {
+		if next := s.nextSchedule(now.Add(-time.Minute)); !next.After(now) {
+			return now
+		}
+	}
+	return s.nextSchedule(now)
+}
+
+// firstRunFrom returns the first run starting from the given DateTime, for frequently option
+// it's exactly at the given DateTime.
+func (s *Tasks) firstRunFrom(start time.Time) time.Time {
+	if s.RunType == _frequently && s.nextFunc == nil {
+		return start
+	}
+	return s.nextSchedule(start)
+}
+
+// storeTask puts the task to the task list as is, a new task ID is assigned if it doesn't have one yet.
+// It returns an error if the task name is already in the task list or the task list is full.
+func (t *TaskScheduler) storeTask(newTask Tasks) error {
+	if len(newTask.id) == 0 {
+		newTask.id = uuid.New().String()
+	}
+	newTask.prepareGate()
+	t.mu.Lock()
+	if _, ok := t.TaskList[newTask.Name]; ok {
+		t.mu.Unlock()
+		msg := newTask.Name + " is not added: " + ErrTaskExists.Error()
+		logger().Errorw(msg, newTask.logKV()...)
+		color.Red(msg)
+		return fmt.Errorf("%s: %w", newTask.Name, ErrTaskExists)
+	}
+	if err := t.checkMaxTasks(newTask.Name); err != nil {
+		t.mu.Unlock()
+		msg := newTask.Name + " is not added: " + err.Error()
+		logger().Errorw(msg, newTask.logKV()...)
+		color.Red(msg)
+		return fmt.Errorf("%s: %w", newTask.Name, err)
+	}
+	t.TaskList[newTask.Name] = []Tasks{newTask}
+	t.mu.Unlock()
+	t.notify()
+	t.emit(ChangeAdded, &newTask)
+	t.gate(newTask)
+
+	// Format next scheduled run
+	nextSched, _ := formatDT(newTask.nextRunTime, logDateTimeFormat)
+	msg := newTask.Name + " base start datetime at: " + nextSched
+	logger().Infow(msg, newTask.logKV()...)
+	color.Cyan(msg)
+	return nil
+}
+
+// nextSchedule computes the next run of the recurring run types after the 'now' time
+func (s *Tasks) nextSchedule(now time.Time) time.Time {
+	var nextSchedToRun time.Time
+	loc := s.location()
 
+	if s.nextAt != nil {
+		nextSchedToRun = s.nextAt()
+		if !nextSchedToRun.IsZero() && nextSchedToRun.Before(now) {
+			nextSchedToRun = now
+		}
+		return nextSchedToRun
+	}
+	if s.nextFunc != nil {
+		if wait := s.nextFunc(); wait > 0 {
+			nextSchedToRun = now.Add(wait)
+		}
+		return nextSchedToRun
+	}
+
+	// For OneTime method, no need to auto-create new schedule to run since it's a onetime run only.
 	switch s.RunType {
 	case _onetime:
-		nextSchedToRun = s.nextRunTime
 
 	case _frequently:
-		if s.FrequencyInterval == _seconds {
-			nextSchedToRun = time.Now().Add(time.Second * time.Duration(s.FrequencyValue)).Unix()
-		}
-		if s.FrequencyInterval == _minutes {
-			nextSchedToRun = time.Now().Add(time.Minute * time.Duration(s.FrequencyValue)).Unix()
-		}
-		if s.FrequencyInterval == _hours {
-			nextSchedToRun = time.Now().Add(time.Hour * time.Duration(s.FrequencyValue)).Unix()
+		if interval := s.interval(); interval > 0 && (s.hasPhase || s.epochAligned) {
+			nextSchedToRun = s.phasedRun(now)
+		} else if interval > 0 {
+			nextSchedToRun = now.Add(interval)
 		}
 
 	case _daily:
 		runHour, _ := strconv.Atoi(s.runAtHour)
 		runMinute, _ := strconv.Atoi(s.runAtMinute)
+		today := now.In(loc)
+
+		// Today if the 'At' time is still ahead, otherwise tomorrow
 		nextSchedToRun = time.Date(
-			time.Now().Year(),
-			time.Now().Month(),
-			time.Now().Day()+1,
-			runHour, runMinute, 0, 0, time.Local).Unix()
+			today.Year(),
+			today.Month(),
+			today.Day(),
+			runHour, runMinute, 0, 0, loc)
+		if !nextSchedToRun.After(now) {
+			nextSchedToRun = nextSchedToRun.AddDate(0, 0, 1)
+		}
 
 	case _weekly:
 		runHour, _ := strconv.Atoi(s.runAtHour)
 		runMinute, _ := strconv.Atoi(s.runAtMinute)
-		today := time.Now()
+		today := now.In(loc)
 
+		// This week's day if the 'At' time is still ahead, otherwise next week's
 		nextSchedToRun = time.Date(
 			today.Year(),
 			today.Month(),
-			today.Day()-int(today.Weekday()-s.dayName),
+			today.Day()+int(s.dayName-today.Weekday()+7)%7,
 			runHour, runMinute, 0, 0,
-			time.Local).Add(7 * 24 * time.Hour).Unix()
+			loc)
+		if !nextSchedToRun.After(now) {
+			nextSchedToRun = nextSchedToRun.AddDate(0, 0, 7)
+		}
+		if s.weekOfMonth > 0 {
+			nextSchedToRun = s.inWeekRun(nextSchedToRun)
+		}
 
 	case _monthly:
 		runHour, _ := strconv.Atoi(s.runAtHour)
 		runMinute, _ := strconv.Atoi(s.runAtMinute)
-		today := time.Now()
+		today := now.In(loc)
+		if s.strictMonthDay && s.requestedDay > 0 {
+			nextSchedToRun = s.strictMonthlyRun(today, runHour, runMinute)
+			break
+		}
 
 		nextSchedToRun = time.Date(
 			today.Year(),
 			today.Month()+1,
 			s.monthDay,
 			runHour, runMinute, 0, 0,
-			time.Local).Unix()
+			loc)
 
 	default:
 		msg := s.Name + " is not running due to incorrect or missing parameters"
-		itrlog.Errorw(msg, "log_time", time.Now().Format(logDateTimeFormat))
+		logger().Errorw(msg, s.logKV()...)
 		color.Red(msg)
 	}
+	return nextSchedToRun
+}
 
-	newTask := Tasks{
-		Name:              s.Name,
-		RunType:           s.RunType,
-		FrequencyInterval: s.FrequencyInterval,
-		FrequencyValue:    s.FrequencyValue,
-		ExecuteFunc:       s.ExecuteFunc,
-		runAtHour:         s.runAtHour,
-		runAtMinute:       s.runAtMinute,
-		monthDay:          s.monthDay,
-		monthName:         s.monthName,
-		isRunAt:           s.isRunAt,
-		nextRunTime:       nextSchedToRun,
-		lastRunTime:       0,
-		created:           time.Now().Unix(),
+// Run executes the task scheduler's individual task item, a second call while it's running returns right away
+func Run() {
+	if !TS.startLoop() {
+		return
 	}
-	TS.TaskList[s.Name] = []Tasks{newTask}
+	defer TS.endLoop()
 
-	// Format next scheduled run
-	nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
-	msg := s.Name + " base start datetime at: " + nextSched
-	itrlog.Infow(msg, "log_time", time.Now().Format(logDateTimeFormat))
-	color.Cyan(msg)
+	TS.runTimer(context.Background(), ChannelTS)
+	TS.Reset()
 }
 
-// Run executes the task scheduler's individual task item
-func Run() {
-mainloop:
+// RunContext runs the due tasks of the task scheduler like 'Run' until the context is done, a message to
+// 'ChannelTS' also stops the default task scheduler. Once the context is done, no new run starts and the
+// contexts of the 'ExecFuncCtx' runs in progress are cancelled, the tasks are kept as is so 'Reset' is optional.
+func (t *TaskScheduler) RunContext(ctx context.Context) {
+	if !t.startLoop() {
+		return
+	}
+	defer t.endLoop()
+
+	var quit <-chan bool
+	if t == &TS {
+		quit = ChannelTS
+	}
+	t.runTimer(ctx, quit)
+	if ctx.Err() != nil {
+		t.cancelRunsContext()
+	}
+}
+
+// RunWhenReady waits until at least one task is added to the task scheduler or any of its namespaces,
+// then it runs the due tasks like 'Run' without spinning idly before. It returns when the context is done
+// or a critical task fails, the tasks are kept as is.
+func (t *TaskScheduler) RunWhenReady(ctx context.Context) {
+	if !t.startLoop() {
+		return
+	}
+	defer t.endLoop()
+
+	for t.taskCount() == 0 {
+		select {
+		case <-t.wakeChannel():
+		case cause := <-t.haltChannel():
+			logStopped(cause)
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+	t.runTimer(ctx, nil)
+}
+
+// runTimer runs the due tasks on time until the context is done, a message is received from the
+// quit channel or the task scheduler is stopped
+func (t *TaskScheduler) runTimer(ctx context.Context, quit <-chan bool) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
 	for {
-		for _, e := range TS.TaskList {
-			for _, s := range e {
-				// Check if due for execution
-				unixTimeNow := time.Now().Unix()
-				if s.nextRunTime == unixTimeNow {
-					TS.UpdateNextRunTime(&s)
-					go s.ExecuteFunc()
-				}
+		t.runPending(time.Now())
+
+		// Sleep until the earliest next run, or until the task list changes
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
 			}
 		}
-		time.Sleep(300 * time.Millisecond)
+		if wait, ok := t.untilNextRun(); ok {
+			timer.Reset(wait)
+		}
 		select {
-		case msg := <-ChannelTS:
+		case <-timer.C:
+		case <-t.wakeChannel():
+		case msg := <-quit:
 			fmt.Println("channel message: ", msg)
-			break mainloop
-		default:
+			return
+		case cause := <-t.haltChannel():
+			logStopped(cause)
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunWithTicker runs the due tasks of the task scheduler on every tick instead of its own timer, the tick
+// time is used as the current time to check the due tasks. It returns when the context is done or the tick
+// channel is closed or a critical task fails, the tasks are kept as is.
+func (t *TaskScheduler) RunWithTicker(ctx context.Context, tick <-chan time.Time) {
+	if !t.startLoop() {
+		return
+	}
+	defer t.endLoop()
 
+	for {
+		select {
+		case cause := <-t.haltChannel():
+			logStopped(cause)
+			return
+		case now, ok := <-tick:
+			if !ok {
+				return
+			}
+			t.runPending(now)
+		case <-ctx.Done():
+			return
 		}
 	}
-	TS.Reset()
 }
 
-// UpdateNextRunTime modify the next run time
-func (t *TaskScheduler) UpdateNextRunTime(s *Tasks) {
+// logStopped logs the cause of the stopped running loop
+func logStopped(cause error) {
+	msg := "task scheduler is stopped: " + cause.Error()
+	logger().Errorw(msg, "log_time", time.Now().Format(logDateTimeFormat))
+	color.Red(msg)
+}
+
+// startLoop marks the running loop as started, it returns false with a warning if the task scheduler
+// already has a running loop, e.g two packages calling 'Run', so the tasks are not executed twice.
+func (t *TaskScheduler) startLoop() bool {
+	// Under the lock of 'stop' so a 'Close' either sees the loop to stop or the loop sees it's closed
+	t.loopMu.Lock()
+	if t.isClosed() {
+		t.loopMu.Unlock()
+		return false
+	}
+	if atomic.CompareAndSwapInt32(&t.running, 0, 1) {
+		t.loopStarted = time.Now()
+		t.loopMu.Unlock()
+		return true
+	}
+	t.loopMu.Unlock()
+	msg := "task scheduler is already running, the second running loop is ignored"
+	logger().Warnw(msg, "log_time", time.Now().Format(logDateTimeFormat))
+	color.Yellow(msg)
+	return false
+}
+
+// endLoop marks the running loop as stopped so it can run again, e.g after a reload. A stop signal
+// that is not received by the loop is dropped so the next loop doesn't stop right away.
+func (t *TaskScheduler) endLoop() {
+	t.loopMu.Lock()
+	t.loopStarted = time.Time{}
+	select {
+	case <-t.haltChannel():
+	default:
+	}
+	atomic.StoreInt32(&t.running, 0)
+	t.loopMu.Unlock()
+}
+
+// SetGracePeriod sets how early a task is considered due before its next run, this trades
+// a little early run for a lower latency when the timer wakes up slightly early.
+func (t *TaskScheduler) SetGracePeriod(d time.Duration) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	t.gracePeriod = d
+}
 
-	var nextSchedToRun int64 = 0
+// SetBatchWindow sets how far ahead the tasks are picked up together with the due tasks, so the tasks that
+// are due within a few milliseconds of each other run in the same pass. Unlike the grace period, the loop
+// doesn't wake up earlier, and the tasks that run early keep their schedule.
+func (t *TaskScheduler) SetBatchWindow(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	t.batchWindow = d
+}
 
-	// For OneTime method, no need to auto-create new schedule to run since it's a onetime run only.
-	switch s.RunType {
-	case _frequently:
-		if s.FrequencyInterval == _seconds {
-			nextSchedToRun = time.Now().Add(time.Second * time.Duration(s.FrequencyValue)).Unix()
+// RunPending runs the due tasks of the task scheduler and its namespaces right away and waits for them,
+// e.g in the tests instead of the running loop. The tasks run one after another in a deterministic order:
+// the tasks of the task scheduler first, then the tasks of each namespace sorted by the namespace name,
+// the tasks are sorted by name within each of them.
+func (t *TaskScheduler) RunPending() {
+	t.dispatch(time.Now(), true)
+}
+
+// runPending dispatches the due tasks of the task scheduler and its namespaces
+func (t *TaskScheduler) runPending(now time.Time) {
+	t.dispatch(now, false)
+}
+
+// dispatch runs the due tasks of the task scheduler and its namespaces, in the background unless
+// it waits for each of them
+func (t *TaskScheduler) dispatch(now time.Time, wait bool) {
+	if t.isClosed() {
+		return
+	}
+	start := time.Now()
+	var lockHeld time.Duration
+	maintenance := t.inMaintenance()
+	for _, ts := range t.withNamespaces() {
+		dueTasks, held := ts.dueTasks(now)
+		lockHeld += held
+		sort.Slice(dueTasks, func(i, j int) bool { return dueTasks[i].Name < dueTasks[j].Name })
+		for _, s := range dueTasks {
+			if maintenance {
+				ts.skipMaintenance(&s, now)
+				continue
+			}
+			if ts.deferBlackout(&s, now) || ts.deferMinGap(&s, now) {
+				continue
+			}
+			runs := s.compensatedRuns(now)
+			ts.UpdateNextRunTime(&s)
+			if !ts.beginRun() {
+				return // Closed in the meantime, no new run starts
+			}
+			if wait {
+				ts.runDue(s, runs)
+			} else {
+				go ts.runDue(s, runs)
+			}
 		}
-		if s.FrequencyInterval == _minutes {
-			nextSchedToRun = time.Now().Add(time.Minute * time.Duration(s.FrequencyValue)).Unix()
+	}
+	t.recordLoop(time.Since(start), lockHeld)
+}
+
+// runDue executes the due task for the number of runs, it's counted in the runs that are in progress
+func (t *TaskScheduler) runDue(s Tasks, runs int) {
+	defer t.inFlight.Done()
+	s.checkMissed(time.Now())
+	for i := 0; i < runs; i++ {
+		t.execute(s)
+	}
+	if len(s.endReason) > 0 && s.onEnd != nil {
+		s.onEnd(s.endReason) // Ended by its schedule, after its last run
+	}
+}
+
+// dueTasks collects the copies of the due tasks and returns how long the lock was held, the copies
+// keep the func that was set at this point
+func (t *TaskScheduler) dueTasks(now time.Time) ([]Tasks, time.Duration) {
+	t.mu.RLock()
+	locked := time.Now()
+	defer t.mu.RUnlock()
+	now = now.Add(t.gracePeriod + t.batchWindow)
+	var dueTasks []Tasks
+	for _, e := range t.TaskList {
+		for _, s := range e {
+			// Check if due for execution
+			if !s.nextRunTime.IsZero() && !s.nextRunTime.After(now) && s.waitFor == nil && !s.paused {
+				dueTasks = append(dueTasks, s)
+			}
 		}
-		if s.FrequencyInterval == _hours {
-			nextSchedToRun = time.Now().Add(time.Hour * time.Duration(s.FrequencyValue)).Unix()
+	}
+	return dueTasks, time.Since(locked)
+}
+
+// untilNextRun returns how long to wait until the earliest next run of the task scheduler and
+// its namespaces, false if there's none
+func (t *TaskScheduler) untilNextRun() (time.Duration, bool) {
+	var earliest time.Time
+	for _, ts := range t.withNamespaces() {
+		ts.mu.RLock()
+		for _, e := range ts.TaskList {
+			for _, s := range e {
+				if s.nextRunTime.IsZero() || s.waitFor != nil || s.paused {
+					continue
+				}
+				if due := s.nextRunTime.Add(-ts.gracePeriod); earliest.IsZero() || due.Before(earliest) {
+					earliest = due
+				}
+			}
 		}
+		ts.mu.RUnlock()
+	}
+	if earliest.IsZero() {
+		return 0, false
+	}
+	wait := time.Until(earliest)
+	if wait < _minInterval && t.inMaintenance() {
+		wait = _minInterval // The held onetime tasks are checked again later
+	}
+	return wait, true
+}
 
-	case _daily:
-		runHour, _ := strconv.Atoi(s.runAtHour)
-		runMinute, _ := strconv.Atoi(s.runAtMinute)
-		nextSchedToRun = time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day()+1, runHour, runMinute, 0, 0, time.Local).Unix()
+// wakeChannel returns the channel that is signaled when the task list changes
+func (t *TaskScheduler) wakeChannel() chan struct{} {
+	t.wakeOnce.Do(func() {
+		t.wake = make(chan struct{}, 1)
+	})
+	return t.wake
+}
 
-	case _weekly:
-		runHour, _ := strconv.Atoi(s.runAtHour)
-		runMinute, _ := strconv.Atoi(s.runAtMinute)
-		today := time.Now()
+// notify wakes up the running loop to pick up the changes in the task list
+func (t *TaskScheduler) notify() {
+	if t.parent != nil {
+		t.parent.notify() // Namespaces share the loop of their parent
+		return
+	}
+	select {
+	case t.wakeChannel() <- struct{}{}:
+	default:
+	}
+}
 
-		nextSchedToRun = time.Date(
-			today.Year(),
-			today.Month(),
-			today.Day()-int(today.Weekday()-s.dayName),
-			runHour, runMinute, 0, 0,
-			time.Local).Add(7 * 24 * time.Hour).Unix()
+// haltChannel returns the channel that stops the running loop, it's created on first use
+func (t *TaskScheduler) haltChannel() chan error {
+	t.haltOnce.Do(func() {
+		t.halt = make(chan error, 1)
+	})
+	return t.halt
+}
 
-	case _monthly:
-		runHour, _ := strconv.Atoi(s.runAtHour)
-		runMinute, _ := strconv.Atoi(s.runAtMinute)
-		today := time.Now()
+// stop signals the running loop to stop with the cause, the loop of the parent is stopped for the namespaces.
+// Nothing is signaled without a running loop, e.g a critical task that fails with 'RunPending'.
+func (t *TaskScheduler) stop(cause error) {
+	if t.parent != nil {
+		t.parent.stop(cause)
+		return
+	}
+	t.loopMu.Lock()
+	defer t.loopMu.Unlock()
+	if atomic.LoadInt32(&t.running) == 0 {
+		return
+	}
+	select {
+	case t.haltChannel() <- cause:
+	default:
+	}
+}
 
-		nextSchedToRun = time.Date(
-			today.Year(),
-			today.Month()+1,
-			s.monthDay,
-			runHour, runMinute, 0, 0,
-			time.Local).Unix()
+// runContext returns the context of the run, it's cancelled at the next run with the 'DeadlineAtNextRun' method
+func (t *TaskScheduler) runContext(s *Tasks) (context.Context, context.CancelFunc) {
+	parent := t.runsContext()
+	if !s.deadlineAtNextRun {
+		return context.WithCancel(parent)
+	}
+	t.mu.RLock()
+	var next time.Time
+	if cur, ok := t.TaskList[s.Name]; ok && len(cur) > 0 {
+		next = cur[0].nextRunTime
+	}
+	t.mu.RUnlock()
+	if next.IsZero() {
+		return context.WithCancel(parent)
+	}
+	return context.WithDeadline(parent, next)
+}
 
-	default:
-		msg := s.Name + " is not running due to incorrect or missing parameters"
-		itrlog.Errorw(msg, "log_time", time.Now().Format(logDateTimeFormat))
+// execute runs the user's defined func of the task
+func (t *TaskScheduler) execute(s Tasks) {
+	if !s.hasFunc() {
+		return
+	}
+	var err error
+	start := time.Now()
+	defer t.startRun(s.Name)()
+	defer t.watchRun(&s, start)()
+	if s.minGap > 0 {
+		t.markRunning(s.Name)
+	}
+	switch {
+	case s.ExecuteFuncCtx != nil:
+		ctx, cancel := t.runContext(&s)
+		err = s.callWithRetry(func() error {
+			return t.guard(&s, func() error { return s.ExecuteFuncCtx(ctx) })
+		})
+		cancel()
+	case s.ExecuteFuncMeta != nil:
+		meta := RunMeta{Name: s.Name, ID: s.id, Scheduled: s.nextRunTime, Started: start}
+		err = s.callWithRetry(func() error {
+			return t.guard(&s, func() error { return s.ExecuteFuncMeta(meta) })
+		})
+	case s.ExecuteFuncErr != nil:
+		err = s.callWithRetry(func() error { return t.guard(&s, s.ExecuteFuncErr) })
+	case s.ExecuteFuncShard != nil:
+		err = t.runShards(&s)
+	case s.ExecuteFunc != nil:
+		err = t.guard(&s, func() error {
+			s.ExecuteFunc()
+			return nil
+		})
+	}
+	for i := 0; err == nil && i < len(s.then); i++ {
+		err = s.checkSuccess(t.guard(&s, s.then[i]))
+	}
+
+	// The function may direct its own next run instead of reporting an error
+	var directive *RunDirective
+	if errors.As(err, &directive) {
+		t.recordRun(s.Name, time.Since(start), nil)
+		t.applyDirective(&s, directive)
+		return
+	}
+	logErr, repeated := t.recordRun(s.Name, time.Since(start), err)
+	if s.adaptive != nil {
+		t.adaptInterval(&s)
+	}
+	if repeated > 0 {
+		msg := fmt.Sprintf("%s: the previous error is repeated %d times", s.Name, repeated)
+		logger().Errorw(msg, s.logKV()...)
 		color.Red(msg)
 	}
 
+	if err != nil {
+		if logErr {
+			msg := s.Name + " returns an error: " + err.Error()
+			logger().Errorw(msg, s.logKV()...)
+			color.Red(msg)
+		}
+		if s.critical {
+			t.stop(fmt.Errorf("critical task %s failed: %w", s.Name, err))
+		}
+		return
+	}
+
+	if s.untilSuccess {
+		if t.endTask(&s, EndSuccess, "after a successful run") && s.onEnd != nil {
+			s.onEnd(EndSuccess)
+		}
+	}
+}
+
+// endTask removes the task from the task list once it has ended and keeps the reason for its 'OnEnd' func,
+// it returns false if the task has been removed in the meantime.
+func (t *TaskScheduler) endTask(s *Tasks, reason, desc string) bool {
+	t.mu.Lock()
+	cur, ok := t.TaskList[s.Name]
+	if ok && len(cur) > 0 {
+		cur[0].ungate()
+	}
+	delete(t.TaskList, s.Name)
+	t.mu.Unlock()
+	if !ok {
+		return false
+	}
+	s.endReason = reason
+	t.emit(ChangeRemoved, s)
+
+	msg := s.Name + " is removed " + desc
+	logger().Infow(msg, s.logKV()...)
+	color.Cyan(msg)
+	return true
+}
+
+// recordRun adds the completed run to the task's statistics, it returns if the error can be logged and
+// how many times the previous error was repeated without being logged
+func (t *TaskScheduler) recordRun(taskName string, d time.Duration, err error) (bool, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Counted even if the task is gone, e.g the last run of a task that reached its limit
+	t.root().countExecution()
+	cur, ok := t.TaskList[taskName]
+	if !ok || len(cur) == 0 {
+		return true, 0
+	}
+	cur[0].lastRunEnd = time.Now()
+	switch {
+	case cur[0].released > 0:
+		cur[0].released-- // Already not counted by the watchdog
+	case cur[0].running > 0:
+		cur[0].running--
+	}
+	cur[0].stats.Runs++
+	cur[0].stats.LastDuration = d
+	cur[0].stats.TotalDuration += d
+	if err != nil {
+		cur[0].stats.Errors++
+		cur[0].lastErr = err
+		cur[0].lastErrAt = cur[0].lastRunEnd
+	} else {
+		cur[0].lastErr = nil // Cleared on the next success
+		cur[0].lastErrAt = time.Time{}
+	}
+	return t.dedupeError(&cur[0], err)
+}
+
+// UpdateNextRunTime modify the next run time
+func (t *TaskScheduler) UpdateNextRunTime(s *Tasks) {
+	// The task is done once it reached its limit, the current run is the last one
+	if s.limit > 0 && s.runCount+1 >= s.limit {
+		t.endTask(s, EndLimit, "after reaching its limit of "+strconv.Itoa(s.limit)+" runs")
+		return
+	}
+
+	if s.onDates && len(s.dates) == 0 {
+		t.endTask(s, EndLastRun, "after its last run")
+		return
+	}
+
+	// A task picked up early within the grace period is scheduled from its due time
+	base := time.Now()
+	if s.nextRunTime.After(base) {
+		base = s.nextRunTime
+	}
+	nextSchedToRun := s.nextSchedule(base)
+	if s.fixedRate && s.RunType == _frequently && s.nextFunc == nil && !s.nextRunTime.IsZero() {
+		nextSchedToRun = s.nextFixedRate(time.Now())
+	}
+	if s.firstRunAt.After(base) {
+		nextSchedToRun = s.firstRunAt // Back to the schedule after the immediate run
+	}
+	if s.onDates {
+		nextSchedToRun = s.dates[0]
+	}
+	if s.pendingBackfill > 1 {
+		nextSchedToRun = time.Now() // Catch up on the next missed run right away
+	}
+	if nextSchedToRun.IsZero() && s.nextFunc != nil {
+		t.endTask(s, EndNoNextRun, "as there's no next schedule to run")
+		return
+	}
+	if !s.until.IsZero() && nextSchedToRun.After(s.until) {
+		t.endTask(s, EndDateRange, "after the end of its date range")
+		return
+	}
+
+	t.mu.Lock()
+	logNextSched := false
+
+	// Only the schedule is updated in place, the func may have been replaced after the task was picked up.
+	// A task that has been removed in the meantime stays removed.
+	if cur, ok := t.TaskList[s.Name]; ok && len(cur) > 0 {
+		logNextSched = t.allowLog(&cur[0])
+		cur[0].nextRunTime = nextSchedToRun
+		cur[0].lastRunTime = time.Now()
+		cur[0].runCount++
+		cur[0].firstRunAt = time.Time{}
+		if cur[0].onDates && len(cur[0].dates) > 0 {
+			cur[0].dates = cur[0].dates[1:]
+		}
+		if cur[0].pendingBackfill > 0 {
+			cur[0].pendingBackfill--
+		}
+		t.emit(ChangeRescheduled, &cur[0])
+	}
+	t.mu.Unlock()
+
 	// Format next scheduled run
-	if s.RunType != _onetime {
+	if logNextSched && (s.RunType != _onetime || s.onDates) {
 		nextSched, _ := formatDT(nextSchedToRun, logDateTimeFormat)
 		msg := s.Name + " next schedule to run on: " + nextSched
-		itrlog.Infow(msg, "log_time", time.Now().Format(logDateTimeFormat))
+		logger().Infow(msg, s.logKV()...)
 		color.Magenta(msg)
 	}
+}
+
+// SetLogInterval limits the "next schedule" messages of each task to at most once per interval,
+// default is 0 which logs every time the next schedule changes.
+func (t *TaskScheduler) SetLogInterval(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	t.logInterval = d
+}
 
-	modTask := &Tasks{
-		Name:              s.Name,
-		RunType:           s.RunType,
-		FrequencyInterval: s.FrequencyInterval,
-		FrequencyValue:    s.FrequencyValue,
-		ExecuteFunc:       s.ExecuteFunc,
-		runAtHour:         s.runAtHour,
-		runAtMinute:       s.runAtMinute,
-		monthDay:          s.monthDay,
-		monthName:         s.monthName,
-		isRunAt:           s.isRunAt,
-		nextRunTime:       nextSchedToRun,
-		lastRunTime:       time.Now().Unix(),
+// allowLog checks if the "next schedule" message of the task can be logged now and marks it as logged,
+// the lock of the task list must be held.
+func (t *TaskScheduler) allowLog(s *Tasks) bool {
+	now := time.Now()
+	if t.logInterval > 0 && !s.lastLogged.IsZero() && now.Sub(s.lastLogged) < t.logInterval {
+		return false
 	}
-	t.TaskList[s.Name] = []Tasks{*modTask}
+	s.lastLogged = now
+	return true
 }
 
-// Reset clear all scheduled tasks
-func (t *TaskScheduler) Reset() {
+// Reset clear all scheduled tasks, it returns the number of tasks removed including its namespaces
+func (t *TaskScheduler) Reset() int {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-	TS.TaskList = make(map[string][]Tasks)
-	msg := `reloading task schedulers...`
-	itrlog.Warnw(msg, "log_time", time.Now().Format(logDateTimeFormat))
+	removed := len(t.TaskList)
+	namespaces := t.namespaces
+	list := t.TaskList
+	ungateAll(list)
+	t.TaskList = make(map[string][]Tasks)
+	t.namespaces = nil
+	t.mu.Unlock()
+	for _, e := range list {
+		for i := range e {
+			t.emit(ChangeRemoved, &e[i])
+		}
+	}
+	for _, ns := range namespaces {
+		removed += ns.taskCount()
+	}
+
+	msg := fmt.Sprintf("reloading task schedulers, %d tasks are removed...", removed)
+	logger().Warnw(msg, "log_time", time.Now().Format(logDateTimeFormat))
 	color.Yellow(msg)
+	return removed
 }
 
-// Format the DateTime value
-func formatDT(dt int64, dtFormat string) (string, error) {
-	i, err := strconv.ParseInt(fmt.Sprintf("%v", dt), 10, 64)
-	if err != nil {
-		panic(err)
+// RemoveTask removes the task using the task name or the task ID, it returns false if there's no
+// such task or the task scheduler is closed. A run that is already in progress is not stopped.
+func (t *TaskScheduler) RemoveTask(taskName string) bool {
+	if t.isClosed() {
+		return false
 	}
+	t.mu.Lock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		t.mu.Unlock()
+		return false
+	}
+	s := taskData[0]
+	taskData[0].ungate()
+	delete(t.TaskList, s.Name)
+	t.emit(ChangeRemoved, &s)
+	t.mu.Unlock()
+
+	msg := s.Name + " is removed"
+	logger().Infow(msg, s.logKV()...)
+	color.Cyan(msg)
+	return true
+}
+
+// Format the DateTime value
+func formatDT(dt time.Time, dtFormat string) (string, error) {
 	if len(strings.TrimSpace(dtFormat)) == 0 {
 		dtFormat = logDateTimeFormat
 	}
-	dtf := time.Unix(i, 0).Format(dtFormat)
+	dtf := dt.Format(dtFormat)
 	return dtf, nil
 }
 
+// parseClockTime parses the 24-hour clock in "15:04" or "15:04:05" format
+func parseClockTime(v string) (hour, minute, second int, err error) {
+	parts := strings.Split(strings.TrimSpace(v), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, fmt.Errorf("invalid time %q, use the 24-hour format e.g 15:04", v)
+	}
+
+	values := [3]int{}
+	limits := [3]int{23, 59, 59}
+	for i, p := range parts {
+		if len(p) != 2 || p[0] < '0' || p[0] > '9' || p[1] < '0' || p[1] > '9' {
+			return 0, 0, 0, fmt.Errorf("invalid time %q, use the 24-hour format e.g 15:04", v)
+		}
+		values[i] = int(p[0]-'0')*10 + int(p[1]-'0')
+		if values[i] > limits[i] {
+			return 0, 0, 0, fmt.Errorf("invalid time %q, use the 24-hour format e.g 15:04", v)
+		}
+	}
+	return values[0], values[1], values[2], nil
+}
+
 // Get the last day of each current month
 func getLastDayOfMonth(day int, month time.Month) int {
 	// Get the current DateTime and get the last day of this month
@@ -0,0 +1,890 @@
+package isked
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetReturnsCopy(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("copy").Frequently().Seconds(1).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+
+	l, ok := ts.Get("copy")
+	if !ok || len(l) != 1 {
+		t.Fatalf("Get = %v, %v", l, ok)
+	}
+	l[0].nextRunTime = time.Time{}
+	if info, _ := ts.Info("copy"); info.NextRun.IsZero() {
+		t.Fatal("changing the result of Get changed the task list")
+	}
+}
+
+// TestGetWhileRunning is meant for 'go test -race', Get must not share the task list with the running loop
+func TestGetWhileRunning(t *testing.T) {
+	ts := newTestScheduler()
+	for i := 0; i < 4; i++ {
+		if _, err := ts.addTask(newTestTask("race" + strconv.Itoa(i)).Frequently().Seconds(1).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ts.RunContext(ctx)
+	}()
+	for ctx.Err() == nil {
+		for i := 0; i < 4; i++ {
+			if l, ok := ts.Get("race" + strconv.Itoa(i)); ok {
+				_ = l[0].nextRunTime
+				_ = l[0].runCount
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	wg.Wait()
+}
+
+// BenchmarkUpdateNextRunTimeContention updates the next runs while other goroutines read the task list
+func BenchmarkUpdateNextRunTimeContention(b *testing.B) {
+	ts := newTestScheduler()
+	const tasks = 100
+	for i := 0; i < tasks; i++ {
+		if _, err := ts.addTask(newTestTask("bench" + strconv.Itoa(i)).Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+			b.Fatal(err)
+		}
+	}
+	copies := make([]Tasks, tasks)
+	for i := range copies {
+		l, _ := ts.Get("bench" + strconv.Itoa(i))
+		copies[i] = l[0]
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%4 == 0 {
+				s := copies[i%tasks]
+				ts.UpdateNextRunTime(&s)
+			} else {
+				ts.Info("bench" + strconv.Itoa(i%tasks))
+			}
+			i++
+		}
+	})
+}
+
+func TestRunPrecision(t *testing.T) {
+	ts := newTestScheduler()
+	start := time.Now().Add(300 * time.Millisecond)
+	ran := make(chan time.Time, 1)
+	s := newTestTask("precise").Frequently().Seconds(10).StartingFrom(start).ExecFunc(func() {
+		select {
+		case ran <- time.Now():
+		default:
+		}
+	})
+	if _, err := ts.addTask(s); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ts.RunContext(ctx)
+	select {
+	case at := <-ran:
+		if late := at.Sub(start); late < 0 || late > 50*time.Millisecond {
+			t.Fatalf("ran %v after its start, want within 50ms", late)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("task didn't run")
+	}
+}
+
+// BenchmarkUntilNextRun is the cost of finding the earliest deadline on each wake up, it's paid instead of
+// keeping a timer per task
+func BenchmarkUntilNextRun(b *testing.B) {
+	for _, n := range []int{10, 1000, 10000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			ts := newTestScheduler()
+			for i := 0; i < n; i++ {
+				s := newTestTask("timer" + strconv.Itoa(i)).Frequently().Seconds(1 + i%3600).ExecFunc(func() {})
+				if _, err := ts.addTask(s); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ts.untilNextRun()
+			}
+		})
+	}
+}
+
+// BenchmarkTimerPerTask is the alternative, arming and stopping a timer for each task
+func BenchmarkTimerPerTask(b *testing.B) {
+	for _, n := range []int{10, 1000, 10000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				timers := make([]*time.Timer, n)
+				for j := range timers {
+					timers[j] = time.AfterFunc(time.Hour+time.Duration(j)*time.Second, func() {})
+				}
+				for _, tm := range timers {
+					tm.Stop()
+				}
+			}
+		})
+	}
+}
+
+func TestAddTaskToNamespace(t *testing.T) {
+	ts := newTestScheduler()
+	ns := ts.Namespace("billing")
+	first, err := ns.AddTask(newTestTask("Invoice").Daily().At("09:00").ExecFunc(func() {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.IsZero() {
+		t.Error("missing first run")
+	}
+	if _, ok := ns.Get("Invoice"); !ok {
+		t.Error("task is not in the namespace")
+	}
+	if _, ok := ts.Get("Invoice"); ok {
+		t.Error("task is in the parent task scheduler")
+	}
+	if got := ts.taskCount(); got != 1 {
+		t.Errorf("taskCount = %d, want 1", got)
+	}
+	if _, err := ns.AddTask(newTestTask("Broken").Daily().ExecFunc(nil)); err == nil {
+		t.Error("invalid task is added")
+	}
+}
+
+func TestRemoveTask(t *testing.T) {
+	logs := useRecordLogger(t)
+	ts := newTestScheduler()
+	runs := 0
+	if _, err := ts.addTask(newTestTask("tenant-report").Daily().At("09:00").LogFields("tenant", "acme").ExecFunc(func() { runs++ })); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "tenant-report")
+	info, _ := ts.Info("tenant-report")
+	if !ts.RemoveTask(info.ID) {
+		t.Fatal("task is not removed by ID")
+	}
+	if _, ok := ts.Get("tenant-report"); ok {
+		t.Error("task is still in the task list")
+	}
+	// It was due, it must not run once removed
+	ts.RunPending()
+	if runs != 0 {
+		t.Errorf("%d runs of the removed task, want 0", runs)
+	}
+	if ts.RemoveTask("tenant-report") {
+		t.Error("removed twice")
+	}
+
+	kv, ok := logs.fields("tenant-report is removed")
+	if !ok {
+		t.Fatal("removal is not logged")
+	}
+	if len(kv) != 4 || kv[2] != "tenant" || kv[3] != "acme" {
+		t.Errorf("removal logged with %v, want the task's log fields", kv)
+	}
+}
+
+func TestSetExecFunc(t *testing.T) {
+	ts := newTestScheduler()
+	var calls []string
+	if _, err := ts.addTask(newTestTask("swap").Frequently().Minutes(1).ExecFunc(func() { calls = append(calls, "old") })); err != nil {
+		t.Fatal(err)
+	}
+
+	// The run picked up before the swap keeps the old func
+	inFlight, _ := ts.Get("swap")
+	if err := ts.SetExecFunc("swap", func() { calls = append(calls, "new") }); err != nil {
+		t.Fatal(err)
+	}
+	ts.execute(inFlight[0])
+
+	cur, _ := ts.Get("swap")
+	if !cur[0].nextRunTime.Equal(inFlight[0].nextRunTime) {
+		t.Errorf("next run moved from %v to %v", inFlight[0].nextRunTime, cur[0].nextRunTime)
+	}
+	makeDue(t, ts, "swap")
+	ts.RunPending()
+	if len(calls) != 2 || calls[0] != "old" || calls[1] != "new" {
+		t.Errorf("calls %v, want [old new]", calls)
+	}
+
+	if err := ts.SetExecFunc("missing", func() {}); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("error %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestGracePeriod(t *testing.T) {
+	ts := newTestScheduler()
+	runs := 0
+	if _, err := ts.addTask(newTestTask("early").Frequently().Minutes(1).ExecFunc(func() { runs++ })); err != nil {
+		t.Fatal(err)
+	}
+	setNextRun(t, ts, "early", time.Now().Add(300*time.Millisecond))
+	ts.RunPending()
+	if runs != 0 {
+		t.Fatal("run before its next run without a grace period")
+	}
+
+	ts.SetGracePeriod(time.Second)
+	if wait, ok := ts.untilNextRun(); !ok || wait > 0 {
+		t.Errorf("wait %v, want the task due right away within the grace period", wait)
+	}
+	ts.RunPending()
+	if runs != 1 {
+		t.Errorf("%d runs, want the slightly early run within the grace period", runs)
+	}
+}
+
+func TestSetLogInterval(t *testing.T) {
+	for _, tt := range []struct {
+		interval time.Duration
+		want     int
+	}{{0, 10}, {time.Hour, 1}} {
+		logs := useRecordLogger(t)
+		ts := newTestScheduler()
+		ts.SetLogInterval(tt.interval)
+		if _, err := ts.addTask(newTestTask("fast").Frequently().Seconds(1).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 10; i++ {
+			makeDue(t, ts, "fast")
+			ts.RunPending()
+		}
+		if got := logs.count("fast next schedule to run on"); got != tt.want {
+			t.Errorf("log interval %v: %d next schedule logs for 10 runs, want %d", tt.interval, got, tt.want)
+		}
+	}
+}
+
+func TestCompensate(t *testing.T) {
+	for _, tt := range []struct {
+		compensate bool
+		want       int
+	}{{false, 1}, {true, 4}} {
+		ts := newTestScheduler()
+		runs := 0
+		s := newTestTask("tick").Frequently().Minutes(1).ExecFunc(func() { runs++ })
+		if tt.compensate {
+			s.Compensate()
+		}
+		if _, err := ts.addTask(s); err != nil {
+			t.Fatal(err)
+		}
+		// The loop is delayed by three and a half intervals
+		setNextRun(t, ts, "tick", time.Now().Add(-210*time.Second))
+		ts.RunPending()
+		if runs != tt.want {
+			t.Errorf("compensate %v: %d runs, want %d", tt.compensate, runs, tt.want)
+		}
+		if info, _ := ts.Info("tick"); !info.NextRun.After(time.Now()) {
+			t.Errorf("compensate %v: next run %v is not in the future", tt.compensate, info.NextRun)
+		}
+	}
+}
+
+func TestStableID(t *testing.T) {
+	ts := newTestScheduler()
+	for _, name := range []string{"first", "second"} {
+		if _, err := ts.addTask(newTestTask(name).Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, _ := ts.Info("first")
+	second, _ := ts.Info("second")
+	if len(first.ID) == 0 || first.ID == second.ID {
+		t.Fatalf("IDs %q and %q, want unique IDs", first.ID, second.ID)
+	}
+
+	makeDue(t, ts, "first")
+	ts.RunPending()
+	if err := ts.Pause("first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Resume("first", ResumeSkipMissed); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := ts.Info("first")
+	if after.ID != first.ID || after.NextRun.Equal(first.NextRun) {
+		t.Errorf("ID %q with the next run %v after the reschedules, want %q with a new next run", after.ID, after.NextRun, first.ID)
+	}
+	if cur, ok := ts.Get(first.ID); !ok || cur[0].Name != "first" {
+		t.Error("Get doesn't accept the ID")
+	}
+}
+
+func TestFirstRunAtCurrentMinute(t *testing.T) {
+	day := time.Date(2030, time.January, 2, 0, 0, 0, 0, time.Local) // Wednesday
+	at := func(h, m, sec int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second)
+	}
+	tests := []struct {
+		name string
+		task *Tasks
+		now  time.Time
+		want time.Time
+	}{
+		{"daily at the start of the minute", newTestTask("d").Daily().At("14:18"), at(14, 18, 0), at(14, 18, 0)},
+		{"daily within the minute", newTestTask("d").Daily().At("14:18"), at(14, 18, 30), at(14, 18, 30)},
+		{"daily at the end of the minute", newTestTask("d").Daily().At("14:18"), at(14, 18, 59), at(14, 18, 59)},
+		{"daily after the minute", newTestTask("d").Daily().At("14:18"), at(14, 19, 0), at(14, 18, 0).AddDate(0, 0, 1)},
+		{"daily before the minute", newTestTask("d").Daily().At("14:18"), at(14, 17, 59), at(14, 18, 0)},
+		{"weekly within the minute", newTestTask("w").Weekly().Wednesday().At("14:18"), at(14, 18, 30), at(14, 18, 30)},
+		{"weekly after the minute", newTestTask("w").Weekly().Wednesday().At("14:18"), at(14, 19, 0), at(14, 18, 0).AddDate(0, 0, 7)},
+		{"weekly on another day", newTestTask("w").Weekly().Thursday().At("14:18"), at(14, 18, 30), at(14, 18, 0).AddDate(0, 0, 1)},
+	}
+	for _, tt := range tests {
+		if got := tt.task.firstRun(tt.now); !got.Equal(tt.want) {
+			t.Errorf("%s: first run %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunWithTicker(t *testing.T) {
+	ts := newTestScheduler()
+	var mu sync.Mutex
+	var scheduled []time.Time
+	if _, err := ts.addTask(newTestTask("ticked").Frequently().Minutes(1).ExecFuncMeta(func(meta RunMeta) error {
+		mu.Lock()
+		defer mu.Unlock()
+		scheduled = append(scheduled, meta.Scheduled)
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+	first := time.Now().Add(time.Hour).Truncate(time.Second)
+	setNextRun(t, ts, "ticked", first)
+
+	tick := make(chan time.Time)
+	done := make(chan struct{})
+	go func() {
+		ts.RunWithTicker(context.Background(), tick)
+		close(done)
+	}()
+	for _, now := range []time.Time{
+		first.Add(-30 * time.Second), // Not due yet
+		first,                        // Due
+		first.Add(30 * time.Second),  // Already ran, due again a minute later
+		first.Add(time.Minute),       // Due
+	} {
+		tick <- now
+	}
+	close(tick)
+	<-done
+	ts.inFlight.Wait()
+
+	sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].Before(scheduled[j]) })
+	if len(scheduled) != 2 || !scheduled[0].Equal(first) || !scheduled[1].Equal(first.Add(time.Minute)) {
+		t.Errorf("runs scheduled at %v, want %v and %v", scheduled, first, first.Add(time.Minute))
+	}
+}
+
+func TestCriticalStopsLoop(t *testing.T) {
+	for _, critical := range []bool{false, true} {
+		ts := newTestScheduler()
+		ran := make(chan struct{}, 1)
+		s := newTestTask("license").Frequently().Minutes(1).ExecFuncErr(func() error {
+			ran <- struct{}{}
+			return errors.New("license expired")
+		})
+		if critical {
+			s.Critical()
+		}
+		if _, err := ts.addTask(s); err != nil {
+			t.Fatal(err)
+		}
+		makeDue(t, ts, "license")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		tick := make(chan time.Time, 1)
+		done := make(chan struct{})
+		go func() {
+			ts.RunWithTicker(ctx, tick)
+			close(done)
+		}()
+		tick <- time.Now()
+		<-ran
+
+		select {
+		case <-done:
+			if !critical {
+				t.Error("loop is stopped by a task that is not critical")
+			}
+		case <-time.After(200 * time.Millisecond):
+			if critical {
+				t.Error("loop is still running after the critical task failed")
+			}
+		}
+		cancel()
+		<-done
+	}
+}
+
+func TestCriticalRunPendingThenRun(t *testing.T) {
+	// A critical failure without a running loop must not stop the loop started later
+	ts := newTestScheduler()
+	var runs int32
+	if _, err := ts.addTask(newTestTask("license").Frequently().Seconds(1).Critical().ExecFuncErr(func() error {
+		atomic.AddInt32(&runs, 1)
+		return errors.New("license expired")
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.addTask(newTestTask("report").Frequently().Minutes(1).ExecFunc(func() {
+		atomic.AddInt32(&runs, 1)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "license")
+	ts.RunPending()
+	ts.RemoveTask("license")
+
+	makeDue(t, ts, "report")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ts.RunContext(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("loop is stopped by the failure before it started")
+	case <-time.After(200 * time.Millisecond):
+	}
+	cancel()
+	<-done
+	ts.inFlight.Wait()
+	if n := atomic.LoadInt32(&runs); n != 2 {
+		t.Errorf("%d runs, want the failed run and the report run", n)
+	}
+}
+
+func TestImmediatelyKeepsSchedule(t *testing.T) {
+	ts := newTestScheduler()
+	// Two hours from now so the daily run is never in the current minute
+	at := time.Now().Add(2 * time.Hour).Format("15:04")
+	if _, err := ts.addTask(newTestTask("scheduled").Daily().At(at).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ts.Info("scheduled")
+
+	runs := 0
+	first, err := ts.addTask(newTestTask("report").Daily().At(at).Immediately().ExecFunc(func() { runs++ }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(first); d < 0 || d > time.Second {
+		t.Fatalf("first run %v, want right away", first)
+	}
+	ts.RunPending()
+	if runs != 1 {
+		t.Fatalf("%d runs, want the immediate run", runs)
+	}
+
+	// Back on the daily run at the 'At' time, not a day after the immediate run
+	info, _ := ts.Info("report")
+	if !info.NextRun.Equal(want.NextRun) {
+		t.Errorf("next run %v after the immediate run, want %v", info.NextRun, want.NextRun)
+	}
+	cur, _ := ts.Get("report")
+	if next := cur[0].nextSchedule(info.NextRun); !next.Equal(info.NextRun.AddDate(0, 0, 1)) {
+		t.Errorf("run after %v is %v, want the same time the next day", info.NextRun, next)
+	}
+}
+
+func TestAddReturnsFirstRun(t *testing.T) {
+	t.Cleanup(func() {
+		TS.RemoveTask("add-first-run")
+		TS.RemoveTask("add-shim")
+	})
+	first, err := newTestTask("add-first-run").Daily().At("10:00").ExecFunc(func() {}).Add()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, ok := TS.Info("add-first-run")
+	if !ok || first.IsZero() || !first.Equal(info.NextRun) {
+		t.Errorf("Add returns %v, want the scheduled %v", first, info.NextRun)
+	}
+	if first.Hour() != 10 || first.Minute() != 0 {
+		t.Errorf("first run %v, want at 10:00", first)
+	}
+
+	first, err = newTestTask("add-invalid").Daily().At("25:00").ExecFunc(func() {}).Add()
+	if err == nil || !first.IsZero() {
+		t.Errorf("invalid task returns %v, %v, want an error and no first run", first, err)
+	}
+	if _, ok := TS.Get("add-invalid"); ok {
+		t.Error("invalid task is added")
+	}
+
+	// The old method still adds the task without returning anything
+	newTestTask("add-shim").Frequently().Minutes(5).ExecFunc(func() {}).AddTask()
+	if _, ok := TS.Get("add-shim"); !ok {
+		t.Error("task is not added by AddTask")
+	}
+}
+
+func TestAddTaskErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		task  *Tasks
+		field string
+	}{
+		{"no-run-type", newTestTask("no-run-type").ExecFunc(func() {}), "RunType"},
+		{"unknown-run-type", &Tasks{Name: "unknown-run-type", RunType: "hourly", ExecuteFunc: func() {}}, "RunType"},
+		{"no-func", newTestTask("no-func").Frequently().Minutes(5), "ExecFunc"},
+		{"bad-at", newTestTask("bad-at").Daily().At("9am").ExecFunc(func() {}), "At"},
+	}
+	for _, tt := range tests {
+		l := useRecordLogger(t)
+		err := tt.task.AddTask()
+		if !hasProblem(err, tt.field) || !strings.Contains(err.Error(), tt.name) {
+			t.Errorf("%s: error %v, want a %s problem of the task", tt.name, err, tt.field)
+		}
+		if _, ok := TS.Get(tt.name); ok {
+			TS.RemoveTask(tt.name)
+			t.Errorf("%s: invalid task is added", tt.name)
+		}
+		// The problem is still logged
+		if l.count(tt.name+" is not added") != 1 {
+			t.Errorf("%s: problem is not logged", tt.name)
+		}
+	}
+}
+
+func TestFixedRateNoDrift(t *testing.T) {
+	const interval, latency, cycles = time.Minute, 1500 * time.Millisecond, 100
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.Local)
+	fixed := newTestTask("fixed").Frequently().Minutes(1).FixedRate()
+	drifting := newTestTask("drifting").Frequently().Minutes(1)
+	fixed.nextRunTime, drifting.nextRunTime = start, start
+
+	// Each run is picked up late by the latency, only the fixed rate keeps its cadence
+	for i := 0; i < cycles; i++ {
+		fixed.nextRunTime = fixed.nextFixedRate(fixed.nextRunTime.Add(latency))
+		drifting.nextRunTime = drifting.nextSchedule(drifting.nextRunTime.Add(latency))
+	}
+	want := start.Add(cycles * interval)
+	if !fixed.nextRunTime.Equal(want) {
+		t.Errorf("fixed rate drifts by %v after %d runs", fixed.nextRunTime.Sub(want), cycles)
+	}
+	if drift := drifting.nextRunTime.Sub(want); drift != cycles*latency {
+		t.Errorf("without the fixed rate drift %v, want %v", drift, cycles*latency)
+	}
+
+	ts := newTestScheduler()
+	if _, err := ts.addTask(fixed.ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	for _, behind := range []time.Duration{latency, 3*interval + latency} {
+		scheduled := time.Now().Add(-behind).Truncate(time.Second)
+		setNextRun(t, ts, "fixed", scheduled)
+		cur, _ := ts.Get("fixed")
+		ts.UpdateNextRunTime(&cur[0])
+		info, _ := ts.Info("fixed")
+		if off := info.NextRun.Sub(scheduled) % interval; off != 0 || !info.NextRun.After(time.Now()) {
+			t.Errorf("behind by %v: next run %v, want the first aligned run after now from %v", behind, info.NextRun, scheduled)
+		}
+		if info.NextRun.Sub(time.Now()) > interval {
+			t.Errorf("behind by %v: next run %v is more than an interval away", behind, info.NextRun)
+		}
+	}
+}
+
+func TestRunTwiceOnDefault(t *testing.T) {
+	logs := useRecordLogger(t)
+	first := make(chan struct{})
+	go func() {
+		Run()
+		close(first)
+	}()
+	waitUntil(t, time.Second, func() bool { return atomic.LoadInt32(&TS.running) == 1 })
+
+	second := make(chan struct{})
+	go func() {
+		Run()
+		close(second)
+	}()
+	select {
+	case <-second:
+	case <-time.After(time.Second):
+		t.Fatal("second Run doesn't return while the first one is running")
+	}
+	if logs.count("already running") != 1 {
+		t.Error("second Run is not reported")
+	}
+
+	ChannelTS <- true
+	select {
+	case <-first:
+	case <-time.After(time.Second):
+		t.Fatal("first Run doesn't stop")
+	}
+	if atomic.LoadInt32(&TS.running) != 0 {
+		t.Error("default task scheduler is still marked as running after its loop stopped")
+	}
+}
+
+func TestDeadlineAtNextRun(t *testing.T) {
+	ts := newTestScheduler()
+	var cancelled time.Time
+	var cause error
+	if _, err := ts.addTask(newTestTask("overrun").Frequently().Seconds(1).DeadlineAtNextRun().ExecFuncCtx(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			cancelled, cause = time.Now(), ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "overrun")
+	ts.RunPending()
+
+	info, _ := ts.Info("overrun")
+	if !errors.Is(cause, context.DeadlineExceeded) {
+		t.Fatalf("overrunning run ends with %v, want the deadline", cause)
+	}
+	if d := cancelled.Sub(info.NextRun); d < 0 || d > 200*time.Millisecond {
+		t.Errorf("run cancelled %v after the next run at %v, want at the slot boundary", d, info.NextRun)
+	}
+
+	// Without the option the run keeps going past the next run
+	ts.RemoveTask("overrun")
+	overran := false
+	if _, err := ts.addTask(newTestTask("free").Frequently().Seconds(1).ExecFuncCtx(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+		case <-time.After(1500 * time.Millisecond):
+			overran = true
+		}
+		return nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "free")
+	ts.RunPending()
+	if !overran {
+		t.Error("run without the deadline is cancelled at the next run")
+	}
+}
+
+func TestDailyNearMidnight(t *testing.T) {
+	day := time.Date(2031, time.March, 10, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		at       string
+		pickedUp time.Time // late or early run of the scheduled time
+		want     time.Time
+	}{
+		{"23:59", day.Add(23*time.Hour + 59*time.Minute + 90*time.Second), day.AddDate(0, 0, 1).Add(23*time.Hour + 59*time.Minute)},
+		{"00:00", day.Add(30 * time.Second), day.AddDate(0, 0, 1)},
+		{"00:00", day.Add(-100 * time.Millisecond), day.AddDate(0, 0, 1)},
+		{"09:00", day.Add(9*time.Hour + 10*time.Minute), day.AddDate(0, 0, 1).Add(9 * time.Hour)},
+	}
+	for _, tt := range tests {
+		s := newTestTask("daily").Daily().At(tt.at)
+		// The next run is computed from the later of the pick up and the scheduled time like 'UpdateNextRunTime'
+		scheduled := tt.want.AddDate(0, 0, -1)
+		base := tt.pickedUp
+		if scheduled.After(base) {
+			base = scheduled
+		}
+		if got := s.nextSchedule(base); !got.Equal(tt.want) {
+			t.Errorf("%s picked up at %v: next run %v, want %v", tt.at, tt.pickedUp, got, tt.want)
+		}
+	}
+}
+
+func TestBatchWindow(t *testing.T) {
+	const n = 20
+	for _, window := range []time.Duration{0, 50 * time.Millisecond} {
+		ts := newTestScheduler()
+		ts.SetBatchWindow(window)
+		var mu sync.Mutex
+		ran := 0
+		first := time.Now().Add(time.Hour).Truncate(time.Second)
+		for i := 0; i < n; i++ {
+			name := "burst-" + strconv.Itoa(i)
+			if _, err := ts.addTask(newTestTask(name).Frequently().Minutes(1).ExecFunc(func() {
+				mu.Lock()
+				ran++
+				mu.Unlock()
+			})); err != nil {
+				t.Fatal(err)
+			}
+			setNextRun(t, ts, name, first.Add(time.Duration(i)*2*time.Millisecond))
+		}
+
+		ts.dispatch(first, true)
+		want := 1
+		if window > 0 {
+			want = n
+		}
+		if ran != want {
+			t.Errorf("window %v: %d tasks ran in the pass, want %d", window, ran, want)
+		}
+		if window == 0 {
+			continue
+		}
+		// The tasks that ran early keep their schedule
+		for i := 0; i < n; i++ {
+			slot := first.Add(time.Duration(i) * 2 * time.Millisecond)
+			if info, _ := ts.Info("burst-" + strconv.Itoa(i)); !info.NextRun.Equal(slot.Add(time.Minute)) {
+				t.Errorf("burst-%d: next run %v, want a minute after its slot %v", i, info.NextRun, slot)
+			}
+		}
+	}
+}
+
+func TestRunWhenReady(t *testing.T) {
+	ts := newTestScheduler()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ts.RunWhenReady(ctx)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if st := ts.LoopStats(); st.Iterations != 0 {
+		t.Fatalf("%d iterations without any task, want the loop to wait", st.Iterations)
+	}
+
+	ran := make(chan struct{}, 1)
+	if _, err := ts.AddTask(newTestTask("first").Frequently().Minutes(1).Immediately().ExecFunc(func() { ran <- struct{}{} })); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("first task doesn't run once it's added")
+	}
+	cancel()
+	<-done
+
+	// Cancelled while it waits for the first task
+	idle := newTestScheduler()
+	ctx, cancel = context.WithCancel(context.Background())
+	done = make(chan struct{})
+	go func() {
+		idle.RunWhenReady(ctx)
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunWhenReady doesn't return when the context is cancelled")
+	}
+}
+
+func TestDueRunsOnce(t *testing.T) {
+	// The ticks never land on the second the task is due, it still runs once on the first tick after it
+	ts := newTestScheduler()
+	var runs int32
+	if _, err := ts.addTask(newTestTask("late-tick").Frequently().Minutes(1).ExecFunc(func() {
+		atomic.AddInt32(&runs, 1)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	due := time.Now().Add(time.Second).Truncate(time.Second)
+	setNextRun(t, ts, "late-tick", due)
+	for _, at := range []time.Duration{-200 * time.Millisecond, 1300 * time.Millisecond, 1500 * time.Millisecond, 2900 * time.Millisecond} {
+		ts.runPending(due.Add(at))
+		ts.inFlight.Wait()
+	}
+	if n := atomic.LoadInt32(&runs); n != 1 {
+		t.Errorf("%d runs on the ticks around the due second, want 1", n)
+	}
+
+	// Same with the running loop and a slow run delaying it
+	ts = newTestScheduler()
+	runs = 0
+	if _, err := ts.addTask(newTestTask("slow").Frequently().Minutes(1).ExecFunc(func() {
+		atomic.AddInt32(&runs, 1)
+		time.Sleep(300 * time.Millisecond)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	setNextRun(t, ts, "slow", time.Now().Add(time.Second))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ts.RunContext(ctx)
+		close(done)
+	}()
+	waitUntil(t, 3*time.Second, func() bool { return atomic.LoadInt32(&runs) > 0 })
+	time.Sleep(500 * time.Millisecond)
+	cancel()
+	<-done
+	ts.inFlight.Wait()
+	if n := atomic.LoadInt32(&runs); n != 1 {
+		t.Errorf("%d runs of the task due in 1 second, want 1", n)
+	}
+}
+
+func TestRunContextReturnsPromptly(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("hourly").Frequently().Hours(1).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			ts.RunContext(ctx)
+			close(done)
+		}()
+		waitUntil(t, time.Second, func() bool { return atomic.LoadInt32(&ts.running) == 1 })
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("RunContext doesn't return once the context is cancelled")
+		}
+		// The tasks are kept so it can run again without adding them
+		if _, ok := ts.Get("hourly"); !ok {
+			t.Fatal("task is removed by cancelling the context")
+		}
+	}
+
+	// The channel still stops the default task scheduler
+	t.Cleanup(ResetDefault)
+	done := make(chan struct{})
+	go func() {
+		TS.RunContext(context.Background())
+		close(done)
+	}()
+	waitUntil(t, time.Second, func() bool { return atomic.LoadInt32(&TS.running) == 1 })
+	ChannelTS <- true
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunContext of the default task scheduler doesn't stop on the channel")
+	}
+}
@@ -0,0 +1,62 @@
+package isked
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShards(t *testing.T) {
+	const n = 4
+	ts := newTestScheduler()
+	var mu sync.Mutex
+	var shards []int
+	var wg sync.WaitGroup
+	wg.Add(n)
+	parallel := true
+	if _, err := ts.addTask(newTestTask("batch").Frequently().Minutes(1).Shards(n).ExecFuncShard(func(shard, total int) {
+		// Every shard waits for the others, so they only finish if they run in parallel
+		wg.Done()
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			mu.Lock()
+			parallel = false
+			mu.Unlock()
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if total != n {
+			t.Errorf("shard %d gets total %d, want %d", shard, total, n)
+		}
+		shards = append(shards, shard)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "batch")
+	ts.RunPending()
+
+	if !parallel {
+		t.Error("shards don't run in parallel")
+	}
+	sort.Ints(shards)
+	for i := 0; i < n; i++ {
+		if len(shards) != n || shards[i] != i {
+			t.Fatalf("shards %v ran, want 0 to %d once each", shards, n-1)
+		}
+	}
+	if info, _ := ts.Info("batch"); info.Stats.Runs != 1 {
+		t.Errorf("%d runs recorded, want 1 for all the shards", info.Stats.Runs)
+	}
+
+	if _, err := ts.addTask(newTestTask("no-shards").Frequently().Minutes(1).Shards(0).ExecFuncShard(func(int, int) {})); !hasProblem(err, "Shards") {
+		t.Errorf("zero shards error %v, want a Shards problem", err)
+	}
+}
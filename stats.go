@@ -0,0 +1,60 @@
+package isked
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// taskStatsJSON is the JSON format of the task statistics
+type taskStatsJSON struct {
+	Name        string  `json:"name"`
+	Runs        int     `json:"runs"`
+	Errors      int     `json:"errors"`
+	LastRun     string  `json:"last_run,omitempty"` // RFC3339, empty if it never ran
+	NextRun     string  `json:"next_run,omitempty"` // RFC3339, empty if there's no next run
+	AvgDuration float64 `json:"avg_duration_seconds"`
+}
+
+// Stats gets the execution statistics of all the tasks using the task name as the key
+func (t *TaskScheduler) Stats() map[string]TaskStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	stats := make(map[string]TaskStats, len(t.TaskList))
+	for name, e := range t.TaskList {
+		if len(e) > 0 {
+			stats[name] = e[0].stats
+		}
+	}
+	return stats
+}
+
+// StatsJSON gets the execution statistics of all the tasks as a JSON array sorted by the task name
+func (t *TaskScheduler) StatsJSON() ([]byte, error) {
+	t.mu.RLock()
+	payLoad := make([]taskStatsJSON, 0, len(t.TaskList))
+	for _, e := range t.TaskList {
+		for _, s := range e {
+			payLoad = append(payLoad, taskStatsJSON{
+				Name:        s.Name,
+				Runs:        s.stats.Runs,
+				Errors:      s.stats.Errors,
+				LastRun:     formatRFC3339(s.lastRunTime),
+				NextRun:     formatRFC3339(s.nextRunTime),
+				AvgDuration: s.stats.AvgDuration().Seconds(),
+			})
+		}
+	}
+	t.mu.RUnlock()
+
+	sort.Slice(payLoad, func(i, j int) bool { return payLoad[i].Name < payLoad[j].Name })
+	return json.Marshal(payLoad)
+}
+
+// formatRFC3339 formats the DateTime in RFC3339, the zero time is an empty string
+func formatRFC3339(dt time.Time) string {
+	if dt.IsZero() {
+		return ""
+	}
+	return dt.Format(time.RFC3339)
+}
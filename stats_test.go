@@ -0,0 +1,149 @@
+package isked
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatsJSON(t *testing.T) {
+	ts := newTestScheduler()
+	for _, name := range []string{"sync", "backup", "report"} {
+		fail := name == "report"
+		if _, err := ts.addTask(newTestTask(name).Frequently().Minutes(1).ExecFuncErr(func() error {
+			if fail {
+				return errors.New("report failed")
+			}
+			return nil
+		})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	makeDue(t, ts, "backup")
+	makeDue(t, ts, "report")
+	ts.RunPending()
+
+	data, err := ts.StatsJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats []map[string]interface{}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("%d entries, want 3", len(stats))
+	}
+	for i, want := range []string{"backup", "report", "sync"} {
+		if stats[i]["name"] != want {
+			t.Errorf("entry %d is %v, want %s sorted by name", i, stats[i]["name"], want)
+		}
+		if _, err := time.Parse(time.RFC3339, stats[i]["next_run"].(string)); err != nil {
+			t.Errorf("%s: next run is not RFC3339: %v", want, err)
+		}
+		if _, ok := stats[i]["avg_duration_seconds"]; !ok {
+			t.Errorf("%s: missing the average duration", want)
+		}
+	}
+	if stats[1]["runs"] != 1.0 || stats[1]["errors"] != 1.0 {
+		t.Errorf("report runs %v errors %v, want 1 and 1", stats[1]["runs"], stats[1]["errors"])
+	}
+	if _, err := time.Parse(time.RFC3339, stats[0]["last_run"].(string)); err != nil {
+		t.Errorf("backup: last run is not RFC3339: %v", err)
+	}
+	if _, ok := stats[2]["last_run"]; ok {
+		t.Error("sync never ran but has a last run")
+	}
+
+	again, _ := ts.StatsJSON()
+	if !bytes.Equal(data, again) {
+		t.Error("StatsJSON is not deterministic")
+	}
+}
+
+func TestLoopStats(t *testing.T) {
+	ts := newTestScheduler()
+	if st := ts.LoopStats(); st.Iterations != 0 || !st.Started.IsZero() || st.IterationsPerSecond != 0 {
+		t.Fatalf("loop stats %+v before the loop ran, want zero", st)
+	}
+
+	ns := ts.Namespace("acme")
+	for _, s := range []struct {
+		ts   *TaskScheduler
+		name string
+	}{{ts, "report"}, {ns, "invoice"}} {
+		if _, err := s.ts.AddTask(newTestTask(s.name).Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+		makeDue(t, s.ts, s.name)
+	}
+	for i := 0; i < 5; i++ {
+		ts.RunPending()
+	}
+
+	st := ts.LoopStats()
+	if st.Iterations != 5 {
+		t.Errorf("%d iterations, want 5", st.Iterations)
+	}
+	if st.Started.IsZero() || st.AvgIteration <= 0 || st.LockHeld <= 0 || st.IterationsPerSecond <= 0 {
+		t.Errorf("loop stats %+v, want the timing to be populated", st)
+	}
+	if st.Executions != 2 {
+		t.Errorf("%d executions, want 2 including the namespace", st.Executions)
+	}
+
+	// The last run of a task that ends itself is counted too
+	if _, err := ts.addTask(newTestTask("limited").Frequently().Minutes(1).Limit(2).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		makeDue(t, ts, "limited")
+		ts.RunPending()
+	}
+	if _, ok := ts.Get("limited"); ok {
+		t.Fatal("task is kept after its limit")
+	}
+	if st := ts.LoopStats(); st.Executions != 4 {
+		t.Errorf("%d executions, want 4 with both runs of the limited task", st.Executions)
+	}
+}
+
+func TestUptime(t *testing.T) {
+	ts := newTestScheduler()
+	if d := ts.Uptime(); d != 0 {
+		t.Fatalf("uptime %v before running, want 0", d)
+	}
+	start := func() (context.CancelFunc, chan struct{}) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			ts.RunWithTicker(ctx, make(chan time.Time))
+			close(done)
+		}()
+		waitUntil(t, time.Second, func() bool { return ts.Uptime() > 0 })
+		return cancel, done
+	}
+
+	cancel, done := start()
+	first := ts.Uptime()
+	time.Sleep(20 * time.Millisecond)
+	if d := ts.Uptime(); d <= first {
+		t.Errorf("uptime %v then %v, want it to increase while running", first, d)
+	}
+	long := ts.Uptime()
+	cancel()
+	<-done
+	if d := ts.Uptime(); d != 0 {
+		t.Errorf("uptime %v after the loop stopped, want 0", d)
+	}
+
+	cancel, done = start()
+	if d := ts.Uptime(); d >= long {
+		t.Errorf("uptime %v after the restart, want it to start again below %v", d, long)
+	}
+	cancel()
+	<-done
+}
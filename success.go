@@ -0,0 +1,32 @@
+package isked
+
+import (
+	"errors"
+)
+
+// ErrRunNotSuccessful is the error of a run with a nil error that the 'SuccessFunc' doesn't count as a success
+var ErrRunNotSuccessful = errors.New("run is not successful")
+
+// SuccessFunc method uses the user's defined func to decide if the error returned by the 'ExecFuncErr',
+// 'ExecFuncCtx' or 'ThenErr' functions counts as a successful run, e.g a benign "no work done" error
+// for the retries, the 'UntilSuccess' method and the task statistics. Default treats a nil error as a success.
+func (s *Tasks) SuccessFunc(fn func(err error) bool) *Tasks {
+	s.success = fn
+	return s
+}
+
+// checkSuccess applies the user's defined success semantics to the error of the func, a run
+// directive is returned as is.
+func (s *Tasks) checkSuccess(err error) error {
+	var directive *RunDirective
+	if s.success == nil || errors.As(err, &directive) {
+		return err
+	}
+	switch {
+	case s.success(err):
+		return nil
+	case err == nil:
+		return ErrRunNotSuccessful
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUntilSuccess(t *testing.T) {
+	ts := newTestScheduler()
+	calls := 0
+	var reason string
+	s := newTestTask("bootstrap").Frequently().Seconds(5).UntilSuccess().ExecFuncErr(func() error {
+		calls++
+		if calls < 4 {
+			return errors.New("dependency is not ready")
+		}
+		return nil
+	}).OnEnd(func(r string) { reason = r })
+	if _, err := ts.addTask(s); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		makeDue(t, ts, "bootstrap")
+		ts.RunPending()
+		if _, ok := ts.Get("bootstrap"); !ok {
+			t.Fatalf("removed after %d failed runs", i)
+		}
+	}
+	makeDue(t, ts, "bootstrap")
+	ts.RunPending()
+	if _, ok := ts.Get("bootstrap"); ok {
+		t.Error("still in the task list after the successful run")
+	}
+	if calls != 4 || reason != EndSuccess {
+		t.Errorf("%d calls and end reason %q, want 4 and %q", calls, reason, EndSuccess)
+	}
+}
+
+func TestSuccessFunc(t *testing.T) {
+	errNoWork := errors.New("no work done")
+	benign := func(err error) bool { return err == nil || errors.Is(err, errNoWork) }
+
+	ts := newTestScheduler()
+	calls := 0
+	if _, err := ts.addTask(newTestTask("drain").Frequently().Minutes(1).SuccessFunc(benign).
+		RetryJitter(time.Millisecond, time.Millisecond, 3).ExecFuncErr(func() error {
+		calls++
+		return errNoWork
+	})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "drain")
+	ts.RunPending()
+	info, _ := ts.Info("drain")
+	if calls != 1 || info.Stats.Errors != 0 || info.LastError != nil {
+		t.Errorf("%d calls, %d errors and last error %v for a benign error, want a single successful call",
+			calls, info.Stats.Errors, info.LastError)
+	}
+
+	// A benign error ends the task that runs until its first success
+	if _, err := ts.addTask(newTestTask("once").Frequently().Minutes(1).UntilSuccess().SuccessFunc(benign).
+		ExecFuncErr(func() error { return errNoWork })); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "once")
+	ts.RunPending()
+	if _, ok := ts.Get("once"); ok {
+		t.Error("task is not ended by a benign error")
+	}
+
+	// A nil error can also be a failure
+	if _, err := ts.addTask(newTestTask("strict").Frequently().Minutes(1).SuccessFunc(func(error) bool { return false }).
+		ExecFuncErr(func() error { return nil })); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, ts, "strict")
+	ts.RunPending()
+	if err, _, _ := ts.LastError("strict"); !errors.Is(err, ErrRunNotSuccessful) {
+		t.Errorf("last error %v, want ErrRunNotSuccessful", err)
+	}
+}
@@ -0,0 +1,226 @@
+package isked
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TaskDef is the definition of a task including its next and last run, it's used to move
+// a task from one task scheduler to another.
+type TaskDef struct {
+	ID             string        `json:"id,omitempty"` // kept on import, a new one is assigned if empty
+	Name           string        `json:"name"`
+	RunType        RunType       `json:"run_type"`
+	Interval       time.Duration `json:"interval,omitempty"`  // frequently option only
+	Weekday        time.Weekday  `json:"weekday,omitempty"`   // weekly option only
+	MonthDay       int           `json:"month_day,omitempty"` // monthly option only
+	At             string        `json:"at,omitempty"`        // 24-hour clock, e.g "15:04"
+	Location       string        `json:"location,omitempty"`  // timezone name, e.g "Asia/Manila", empty for local time
+	Limit          int           `json:"limit,omitempty"`     // maximum number of runs, 0 means no limit
+	RunCount       int           `json:"run_count,omitempty"` // number of runs so far
+	StartingFrom   time.Time     `json:"starting_from"`       // zero if not set
+	Until          time.Time     `json:"until"`               // end of the date range, zero if not set
+	UntilSuccess   bool          `json:"until_success,omitempty"`
+	BlackoutStart  string        `json:"blackout_start,omitempty"` // 24-hour clock, e.g "01:00:00"
+	BlackoutEnd    string        `json:"blackout_end,omitempty"`   // 24-hour clock, e.g "03:30:00"
+	SkipBlackout   bool          `json:"skip_blackout,omitempty"`
+	RunAtStartup   bool          `json:"run_at_startup,omitempty"`
+	Backfill       int           `json:"backfill,omitempty"` // maximum number of missed runs to catch up on import
+	FixedRate      bool          `json:"fixed_rate,omitempty"`
+	Compensate     bool          `json:"compensate,omitempty"`
+	MinGap         time.Duration `json:"min_gap,omitempty"`          // minimum time between the end of a run and the next run
+	Phase          time.Duration `json:"phase,omitempty"`            // frequently option only, offset from the interval boundary
+	HasPhase       bool          `json:"has_phase,omitempty"`        // true if the phase is set, even if it's zero
+	EpochAligned   bool          `json:"epoch_aligned,omitempty"`    // frequently option only
+	StrictMonthDay bool          `json:"strict_month_day,omitempty"` // monthly option only
+	WeekOfMonth    int           `json:"week_of_month,omitempty"`    // weekly option only, 0 for every week
+	Dates          []time.Time   `json:"dates,omitempty"`            // onetime option only, the DateTime to run on after the next run
+	NextRun        time.Time     `json:"next_run"`                   // zero to compute the next run on import
+	LastRun        time.Time     `json:"last_run"`                   // zero if it never ran
+	Created        time.Time     `json:"created"`
+}
+
+// Export gets the definition of the task using the task name or the task ID, use 'Import' to add it to
+// another task scheduler.
+// Tasks that use the 'NextFunc' method can't be exported.
+func (t *TaskScheduler) Export(taskName string) (TaskDef, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	taskData, ok := t.lookup(taskName)
+	if !ok || len(taskData) == 0 {
+		return TaskDef{}, fmt.Errorf("%s: %w", taskName, ErrTaskNotFound)
+	}
+	s := taskData[0]
+	if s.nextFunc != nil {
+		return TaskDef{}, fmt.Errorf("%s: tasks with a next func can't be exported", taskName)
+	}
+	return s.def(), nil
+}
+
+// def converts the task to its definition
+func (s *Tasks) def() TaskDef {
+	def := TaskDef{
+		ID:             s.id,
+		Name:           s.Name,
+		RunType:        RunType(s.RunType),
+		Interval:       s.interval(),
+		Weekday:        s.dayName,
+		WeekOfMonth:    s.weekOfMonth,
+		MonthDay:       s.monthDay,
+		Limit:          s.limit,
+		RunCount:       s.runCount,
+		StartingFrom:   s.startingFrom,
+		Until:          s.until,
+		UntilSuccess:   s.untilSuccess,
+		SkipBlackout:   s.skipBlackout,
+		RunAtStartup:   s.runAtStartup,
+		Backfill:       s.backfill,
+		FixedRate:      s.fixedRate,
+		Compensate:     s.compensate,
+		MinGap:         s.minGap,
+		Phase:          s.phase,
+		HasPhase:       s.hasPhase,
+		EpochAligned:   s.epochAligned,
+		StrictMonthDay: s.strictMonthDay,
+		Dates:          append([]time.Time(nil), s.dates...),
+		NextRun:        s.nextRunTime,
+		LastRun:        s.lastRunTime,
+		Created:        s.created,
+	}
+	if s.isRunAt {
+		def.At = s.runAtHour + ":" + s.runAtMinute
+	}
+	if s.loc != nil {
+		def.Location = s.loc.String()
+	}
+	if s.strictMonthDay {
+		// The day as given, the strict monthly task is never clamped to the end of the month
+		def.MonthDay = s.requestedDay
+	}
+	if s.hasBlackout {
+		def.BlackoutStart = secondsToClock(s.blackoutStart)
+		def.BlackoutEnd = secondsToClock(s.blackoutEnd)
+	}
+	return def
+}
+
+// Import adds the task from its definition with the function to be executed, the next and last
+// run are kept as is. It returns an error if the task name is already in use.
+func (t *TaskScheduler) Import(def TaskDef, fn FuncToExec) error {
+	if t.isClosed() {
+		return ErrSchedulerClosed
+	}
+	if len(def.Name) == 0 {
+		return errors.New("missing task name")
+	}
+	s, err := def.newTask(fn)
+	if err != nil {
+		return fmt.Errorf("%s: %w", def.Name, err)
+	}
+	// The stored next run doesn't skip the checks, e.g a date range that ended since it was exported
+	if err := s.validate(); err != nil {
+		return fmt.Errorf("%s: %w", def.Name, err)
+	}
+	s.defaultLoc = t.defaultLocation()
+	s.weekStart = t.weekStartDay()
+
+	if def.NextRun.IsZero() {
+		_, err = t.addTask(s)
+		return err
+	}
+	s.nextRunTime = def.NextRun
+	s.prepareBackfill(def.NextRun, time.Now())
+	if s.runAtStartup && def.LastRun.IsZero() {
+		// It hasn't run since it was first added, the startup run is still to be done
+		s.firstRunAt = s.nextRunTime
+		s.nextRunTime = time.Now()
+	}
+	s.lastRunTime = def.LastRun
+	s.created = def.Created
+	if s.created.IsZero() {
+		s.created = time.Now()
+	}
+	return t.storeTask(*s)
+}
+
+// newTask validates the definition and creates the task
+func (def TaskDef) newTask(fn FuncToExec) (*Tasks, error) {
+	opts := ScheduleOptions{
+		Name:        def.Name,
+		RunType:     def.RunType,
+		Interval:    def.Interval,
+		Weekday:     def.Weekday,
+		MonthDay:    def.MonthDay,
+		At:          def.At,
+		OneTime:     def.NextRun,
+		ExecuteFunc: fn,
+	}
+	if len(def.Location) > 0 {
+		loc, err := time.LoadLocation(def.Location)
+		if err != nil {
+			return nil, err
+		}
+		opts.Location = loc
+	}
+
+	s, err := opts.newTask()
+	if err != nil {
+		return nil, err
+	}
+	s.Name = def.Name
+	s.id = def.ID
+	s.Limit(def.Limit)
+	s.runCount = def.RunCount
+	if !def.StartingFrom.IsZero() {
+		s.StartingFrom(def.StartingFrom)
+	}
+	if !def.Until.IsZero() {
+		s.Between(def.StartingFrom, def.Until)
+	}
+	if def.UntilSuccess {
+		s.UntilSuccess()
+	}
+	if len(def.BlackoutStart) > 0 || len(def.BlackoutEnd) > 0 {
+		s.Blackout(def.BlackoutStart, def.BlackoutEnd)
+	}
+	if def.SkipBlackout {
+		s.SkipBlackout()
+	}
+	if def.RunAtStartup {
+		s.RunAtStartup()
+	}
+	s.Backfill(def.Backfill)
+	if def.FixedRate {
+		s.FixedRate()
+	}
+	if def.Compensate {
+		s.Compensate()
+	}
+	s.MinGap(def.MinGap)
+	if def.HasPhase {
+		s.Phase(def.Phase)
+	}
+	if def.EpochAligned {
+		s.EpochAligned()
+	}
+	if def.StrictMonthDay {
+		s.StrictMonthDay()
+	}
+	if def.WeekOfMonth > 0 {
+		s.InWeek(def.WeekOfMonth)
+	}
+	if len(def.Dates) > 0 {
+		s.onDates = true
+		s.dates = append([]time.Time(nil), def.Dates...)
+	}
+	if len(s.problems) > 0 {
+		return nil, &ValidationError{Problems: s.problems}
+	}
+	return s, nil
+}
+
+// secondsToClock converts the seconds since midnight to the 24-hour clock, e.g "15:04:05"
+func secondsToClock(secs int) string {
+	return fmt.Sprintf("%02d:%02d:%02d", secs/3600, secs%3600/60, secs%60)
+}
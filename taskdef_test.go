@@ -0,0 +1,281 @@
+package isked
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExportImportPhase(t *testing.T) {
+	src := newTestScheduler()
+	if _, err := src.addTask(newTestTask("phased").Frequently().Hours(1).Phase(17 * time.Minute).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	def, err := src.Export("phased")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !def.HasPhase || def.Phase != 17*time.Minute {
+		t.Fatalf("exported phase %v (set %v), want 17m", def.Phase, def.HasPhase)
+	}
+
+	// Without the next run it's computed again on import, it must still be on the phase
+	def.NextRun = time.Time{}
+	dst := newTestScheduler()
+	if err := dst.Import(def, func() {}); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := dst.Info("phased")
+	if at := info.NextRun; at.Minute() != 17 || at.Second() != 0 {
+		t.Errorf("imported next run %v, want on :17", at)
+	}
+	cur, _ := dst.Get("phased")
+	if next := cur[0].nextSchedule(info.NextRun); next.Minute() != 17 || next.Sub(info.NextRun) != time.Hour {
+		t.Errorf("run after %v is %v, want an hour later on :17", info.NextRun, next)
+	}
+}
+
+func TestTaskDefRoundTrip(t *testing.T) {
+	src := newTestScheduler()
+	tasks := []*Tasks{
+		newTestTask("rate").Frequently().Minutes(5).FixedRate().Compensate().MinGap(30 * time.Second).ExecFunc(func() {}),
+		newTestTask("epoch").Frequently().Minutes(15).EpochAligned().Phase(time.Minute).ExecFunc(func() {}),
+		newTestTask("zero-phase").Frequently().Hours(1).Phase(0).ExecFunc(func() {}),
+	}
+	for _, s := range tasks {
+		if _, err := src.addTask(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, s := range tasks {
+		def, err := src.Export(s.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(def)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded TaskDef
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		dst := newTestScheduler()
+		if err := dst.Import(decoded, func() {}); err != nil {
+			t.Fatalf("%s: %v", s.Name, err)
+		}
+		got, err := dst.Export(s.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.sameSchedule(def) {
+			t.Errorf("%s: imported %+v, want %+v", s.Name, got, def)
+		}
+
+		rec, err := src.MarshalTask(s.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromRec := newTestScheduler()
+		if err := fromRec.UnmarshalTask(rec, func() {}); err != nil {
+			t.Fatalf("%s: %v", s.Name, err)
+		}
+		got, _ = fromRec.Export(s.Name)
+		if !got.sameSchedule(def) {
+			t.Errorf("%s: unmarshaled %+v, want %+v", s.Name, got, def)
+		}
+	}
+}
+
+func TestTaskDefStrictMonthDay(t *testing.T) {
+	src := newTestScheduler()
+	now := time.Now()
+	// The day exists in the month of the first run, a shorter month later must still be an error
+	day := daysIn(time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.Local))
+	if _, err := src.addTask(newTestTask("strict").Monthly().Every(day).At("10:00").StrictMonthDay().ExecFunc(func() {})); err != nil {
+		t.Skip(err)
+	}
+	def, err := src.Export("strict")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !def.StrictMonthDay || def.MonthDay != day {
+		t.Fatalf("exported strict %v on day %d, want day %d", def.StrictMonthDay, def.MonthDay, day)
+	}
+	dst := newTestScheduler()
+	if err := dst.Import(def, func() {}); err != nil {
+		t.Fatal(err)
+	}
+	cur, _ := dst.Get("strict")
+	if !cur[0].strictMonthDay || cur[0].requestedDay != day {
+		t.Errorf("imported strict %v on day %d, want day %d", cur[0].strictMonthDay, cur[0].requestedDay, day)
+	}
+}
+
+func TestExportImportContinuity(t *testing.T) {
+	src := newTestScheduler()
+	if _, err := src.addTask(newTestTask("sync").Frequently().Minutes(10).Limit(5).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	makeDue(t, src, "sync")
+	src.RunPending()
+	before, _ := src.Info("sync")
+
+	def, err := src.Export("sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := newTestScheduler()
+	if err := dst.Import(def, func() {}); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := dst.Info("sync")
+	if after.ID != before.ID || !after.NextRun.Equal(before.NextRun) || !after.LastRun.Equal(before.LastRun) {
+		t.Errorf("imported %s next %v last %v, want %s next %v last %v",
+			after.ID, after.NextRun, after.LastRun, before.ID, before.NextRun, before.LastRun)
+	}
+	if left, _ := dst.Remaining("sync"); left != 4 {
+		t.Errorf("%d runs left after the import, want 4", left)
+	}
+
+	if err := dst.Import(def, func() {}); !errors.Is(err, ErrTaskExists) {
+		t.Errorf("second import error %v, want ErrTaskExists", err)
+	}
+	if _, err := src.Export("missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("export error %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestExportNextFunc(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("custom").NextFunc(func() time.Duration { return time.Hour }).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.Export("custom"); err == nil {
+		t.Error("task with a next func is exported")
+	}
+}
+
+func TestRunAtStartup(t *testing.T) {
+	at := time.Now().Add(2 * time.Hour).Format("15:04")
+	runs := 0
+	fn := func() { runs++ }
+
+	// Fresh start: runs right away then on its schedule
+	src := newTestScheduler()
+	if _, err := src.addTask(newTestTask("warmup").Daily().At(at).RunAtStartup().ExecFunc(fn)); err != nil {
+		t.Fatal(err)
+	}
+	notRun, err := src.Export("warmup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.RunPending()
+	if runs != 1 {
+		t.Fatalf("%d runs on a fresh start, want the startup run", runs)
+	}
+	info, _ := src.Info("warmup")
+	if info.NextRun.Format("15:04") != at {
+		t.Errorf("next run %v after the startup run, want at %s", info.NextRun, at)
+	}
+	ran, err := src.Export("warmup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Restored after the startup run: no run until its schedule
+	restored := newTestScheduler()
+	if err := restored.Import(ran, fn); err != nil {
+		t.Fatal(err)
+	}
+	restored.RunPending()
+	if runs != 1 {
+		t.Errorf("%d runs after restoring a task that already ran, want no startup run", runs)
+	}
+	if got, _ := restored.Info("warmup"); !got.NextRun.Equal(info.NextRun) {
+		t.Errorf("restored next run %v, want %v", got.NextRun, info.NextRun)
+	}
+
+	// Restored before the startup run: it's still done once
+	pending := newTestScheduler()
+	if err := pending.Import(notRun, fn); err != nil {
+		t.Fatal(err)
+	}
+	pending.RunPending()
+	pending.RunPending()
+	if runs != 2 {
+		t.Errorf("%d runs in total after restoring a task that never ran, want 2 with its startup run", runs)
+	}
+	if got, _ := pending.Info("warmup"); got.NextRun.Format("15:04") != at {
+		t.Errorf("next run %v after the restored startup run, want at %s", got.NextRun, at)
+	}
+}
+
+func TestImportValidatesWithNextRun(t *testing.T) {
+	src := newTestScheduler()
+	now := time.Now()
+	if _, err := src.addTask(newTestTask("seasonal").Daily().At("10:00").Between(now.Add(-time.Hour), now.Add(48*time.Hour)).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	def, err := src.Export("seasonal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.NextRun.IsZero() {
+		t.Fatal("exported without the next run")
+	}
+
+	// The range ended since the export
+	ended := def
+	ended.StartingFrom, ended.Until = now.Add(-48*time.Hour), now.Add(-time.Hour)
+	dst := newTestScheduler()
+	if err := dst.Import(ended, func() {}); !hasProblem(err, "Between") {
+		t.Errorf("import error %v, want a Between problem", err)
+	}
+	badAt := def
+	badAt.At = "25:00"
+	if err := dst.Import(badAt, func() {}); err == nil {
+		t.Error("invalid At time is imported")
+	}
+	if dst.taskCount() != 0 {
+		t.Error("invalid definition is imported")
+	}
+}
+
+func TestImportConcurrent(t *testing.T) {
+	src := newTestScheduler()
+	if _, err := src.addTask(newTestTask("sync").Frequently().Minutes(10).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	def, err := src.Export("sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newTestScheduler()
+	var wg sync.WaitGroup
+	var added, exists int32
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch err := dst.Import(def, func() {}); {
+			case err == nil:
+				atomic.AddInt32(&added, 1)
+			case errors.Is(err, ErrTaskExists):
+				atomic.AddInt32(&exists, 1)
+			default:
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if added != 1 || exists != 7 {
+		t.Errorf("%d imports added and %d reported existing, want 1 and 7", added, exists)
+	}
+}
@@ -0,0 +1,31 @@
+package isked
+
+import (
+	"time"
+)
+
+// Template creates a task template with the common settings of several tasks, use its 'New' method
+// to create each task from it, e.g:
+//
+//	tmpl := isked.Template().Daily().At("09:00").In(loc)
+//	tmpl.New("Report", myFunc1).AddTask()
+//	tmpl.New("Backup", myFunc2).AddTask()
+func Template() *Tasks {
+	return &Tasks{
+		monthName: time.Now().Local().Month(),
+	}
+}
+
+// New method creates a task from the template with its own name and function to be executed, the
+// template is not changed so it can be used again. A suffix is added if the name is already in use.
+func (s *Tasks) New(taskName string, fn FuncToExec) *Tasks {
+	newTask := *s
+	newTask.Name = TS.uniqueName(taskName)
+	newTask.id = ""
+	newTask.created = time.Now()
+	newTask.then = append([]FuncToExecErr(nil), s.then...)
+	newTask.dates = append([]time.Time(nil), s.dates...)
+	newTask.logFields = append([]interface{}(nil), s.logFields...)
+	newTask.problems = append([]FieldError(nil), s.problems...)
+	return newTask.ExecFunc(fn)
+}
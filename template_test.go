@@ -0,0 +1,35 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTemplateWithoutName(t *testing.T) {
+	ts := newTestScheduler()
+	_, err := ts.AddTask(Template().Daily().At("09:00").ExecFunc(func() {}))
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || invalid.Problems[0].Field != "Name" {
+		t.Fatalf("error %v, want the missing name", err)
+	}
+	if _, ok := ts.Get(""); ok {
+		t.Error("task is added with an empty name")
+	}
+}
+
+func TestTemplateNew(t *testing.T) {
+	ts := newTestScheduler()
+	tmpl := Template().Daily().At("09:00").Limit(5)
+	for _, name := range []string{"template-report", "template-backup"} {
+		if _, err := ts.AddTask(tmpl.New(name, func() {})); err != nil {
+			t.Fatal(err)
+		}
+		cur, ok := ts.Get(name)
+		if !ok || cur[0].limit != 5 || cur[0].RunType != _daily {
+			t.Errorf("%s: not added with the template settings", name)
+		}
+	}
+	if len(tmpl.Name) > 0 || tmpl.hasFunc() {
+		t.Error("template is changed by New")
+	}
+}
@@ -0,0 +1,47 @@
+package isked
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestThen(t *testing.T) {
+	errStep := errors.New("step failed")
+	var order []string
+	step := func(name string) FuncToExec {
+		return func() { order = append(order, name) }
+	}
+	failing := func(name string) FuncToExecErr {
+		return func() error {
+			order = append(order, name)
+			return errStep
+		}
+	}
+
+	tests := []struct {
+		task *Tasks
+		want []string
+		err  bool
+	}{
+		{newTestTask("in-order").ExecFunc(step("a")).Then(step("b")).Then(step("c")), []string{"a", "b", "c"}, false},
+		{newTestTask("stop-on-then").ExecFunc(step("a")).ThenErr(failing("b")).Then(step("c")), []string{"a", "b"}, true},
+		{newTestTask("stop-on-exec").ExecFuncErr(failing("a")).Then(step("b")), []string{"a"}, true},
+	}
+	for _, tt := range tests {
+		order = nil
+		ts := newTestScheduler()
+		if _, err := ts.addTask(tt.task.Frequently().Minutes(1)); err != nil {
+			t.Fatal(err)
+		}
+		makeDue(t, ts, tt.task.Name)
+		ts.RunPending()
+
+		if !reflect.DeepEqual(order, tt.want) {
+			t.Errorf("%s: ran %v, want %v", tt.task.Name, order, tt.want)
+		}
+		if err, _, _ := ts.LastError(tt.task.Name); tt.err != errors.Is(err, errStep) {
+			t.Errorf("%s: last error %v", tt.task.Name, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package isked
+
+import (
+	"errors"
+	"strings"
+)
+
+// FieldError is the problem of a setting of the task, the field is the method that sets it, e.g "Blackout"
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error returns the field with its problem
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the problem of the field
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is the list of all the problems of a task that is not added, e.g to show each one
+// next to its field, 'errors.Is' matches any of them.
+type ValidationError struct {
+	Problems []FieldError
+}
+
+// Error lists every problem of the task separated with a semicolon
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the problems matches the target
+func (e *ValidationError) Is(target error) bool {
+	for _, p := range e.Problems {
+		if errors.Is(p, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate method checks the task without adding it, it returns the 'ValidationError' with all the problems found.
+func (s *Tasks) Validate() error {
+	return s.validate()
+}
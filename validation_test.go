@@ -0,0 +1,148 @@
+package isked
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hasProblem checks if the error is a ValidationError with a problem of the field
+func hasProblem(err error, field string) bool {
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	for _, p := range invalid.Problems {
+		if p.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIntervalBounds(t *testing.T) {
+	ts := newTestScheduler()
+	tests := []struct {
+		name  string
+		task  *Tasks
+		valid bool
+	}{
+		{"no-interval", newTestTask("no-interval").Frequently(), false},
+		{"one-second", newTestTask("one-second").Frequently().Seconds(1), true},
+		{"one-year", newTestTask("one-year").Frequently().Hours(366 * 24), true},
+		{"above-max", newTestTask("above-max").Frequently().Hours(366*24 + 1), false},
+	}
+	for _, tt := range tests {
+		_, err := ts.addTask(tt.task.ExecFunc(func() {}))
+		if tt.valid && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.valid && !hasProblem(err, "Interval") {
+			t.Errorf("%s: error %v, want an Interval problem", tt.name, err)
+		}
+	}
+}
+
+func TestCombinedIntervalUnits(t *testing.T) {
+	tests := []struct {
+		name string
+		task *Tasks
+		want time.Duration
+	}{
+		{"minutes", newTestTask("minutes").Frequently().Minutes(90), 90 * time.Minute},
+		{"hours-minutes", newTestTask("hours-minutes").Frequently().Hours(1).Minutes(30), 90 * time.Minute},
+		{"minutes-hours", newTestTask("minutes-hours").Frequently().Minutes(30).Hours(1), 90 * time.Minute},
+		{"all-units", newTestTask("all-units").Frequently().Hours(1).Minutes(1).Seconds(1), time.Hour + time.Minute + time.Second},
+		{"same-unit", newTestTask("same-unit").Frequently().Seconds(20).Seconds(10), 30 * time.Second},
+		{"non-positive", newTestTask("non-positive").Frequently().Minutes(0).Seconds(-5), time.Minute + time.Second},
+	}
+	for _, tt := range tests {
+		if got := tt.task.interval(); got != tt.want {
+			t.Errorf("%s: interval %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// The combined interval is checked against the maximum and doesn't overflow
+	ts := newTestScheduler()
+	s := newTestTask("overflow").Frequently().Hours(366 * 24).Seconds(1).Hours(1 << 40)
+	if _, err := ts.addTask(s.ExecFunc(func() {})); !hasProblem(err, "Interval") {
+		t.Errorf("error %v, want an Interval problem", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	s := (&Tasks{}).Daily().At("10:00").Phase(time.Minute).EpochAligned()
+	err := s.Validate()
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("error %v is not a ValidationError", err)
+	}
+	fields := []string{"Name", "ExecFunc", "Phase", "EpochAligned"}
+	if len(invalid.Problems) != len(fields) {
+		t.Errorf("%d problems in %v, want %d", len(invalid.Problems), err, len(fields))
+	}
+	for _, field := range fields {
+		if !hasProblem(err, field) {
+			t.Errorf("missing the %s problem in %v", field, err)
+		}
+	}
+	if got := strings.Count(err.Error(), "; "); got != len(fields)-1 {
+		t.Errorf("error %q doesn't list each problem", err)
+	}
+
+	// Adding it reports the same problems and doesn't add it
+	ts := newTestScheduler()
+	if _, err := ts.addTask(s); !hasProblem(err, "Name") || !hasProblem(err, "EpochAligned") {
+		t.Errorf("add error %v, want all the problems", err)
+	}
+	if ts.taskCount() != 0 {
+		t.Error("invalid task is added")
+	}
+
+	if err := newTestTask("valid").Daily().At("10:00").ExecFunc(func() {}).Validate(); err != nil {
+		t.Errorf("valid task error %v", err)
+	}
+}
+
+func TestAtFormat(t *testing.T) {
+	tests := []struct {
+		at           string
+		hour, minute string
+		valid        bool
+	}{
+		{"15:04", "15", "04", true},
+		{"00:00", "00", "00", true},
+		{"23:59", "23", "59", true},
+		{"24:00", "", "", false},
+		{"15:60", "", "", false},
+		{"25:99", "", "", false},
+		{"9am", "", "", false},
+		{"abc", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		ts := newTestScheduler()
+		s := newTestTask("daily").Daily().At(tt.at).ExecFunc(func() {})
+		_, err := ts.addTask(s)
+		if !tt.valid {
+			if !hasProblem(err, "At") {
+				t.Errorf("%q: error %v, want an At problem", tt.at, err)
+			}
+			if ts.taskCount() != 0 {
+				t.Errorf("%q: task is added to run at midnight", tt.at)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.at, err)
+			continue
+		}
+		if s.runAtHour != tt.hour || s.runAtMinute != tt.minute {
+			t.Errorf("%q: runs at %s:%s", tt.at, s.runAtHour, s.runAtMinute)
+		}
+		if info, _ := ts.Info("daily"); info.NextRun.Format("15:04") != tt.at {
+			t.Errorf("%q: next run %v", tt.at, info.NextRun)
+		}
+	}
+}
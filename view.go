@@ -0,0 +1,59 @@
+package isked
+
+import (
+	"sort"
+	"time"
+)
+
+// SchedulerView is the read-only access to a task scheduler, hand it to the components that
+// should only query the tasks. It always reflects the current task list of the task scheduler.
+type SchedulerView interface {
+	List() []TaskInfo                          // public information of all the tasks sorted by the task name
+	Get(taskName string) (TaskInfo, bool)      // public information of the task using the task name or the task ID
+	Count() int                                // number of tasks, the namespaces are not included
+	Stats() map[string]TaskStats               // execution statistics using the task name as the key
+	NextRun(taskName string) (time.Time, bool) // next run of the task, zero if there's no next run
+}
+
+// schedulerView is the read-only access to the task scheduler
+type schedulerView struct {
+	t *TaskScheduler
+}
+
+// View returns the read-only access to the task scheduler
+func (t *TaskScheduler) View() SchedulerView {
+	return schedulerView{t: t}
+}
+
+func (v schedulerView) List() []TaskInfo {
+	v.t.mu.RLock()
+	list := make([]TaskInfo, 0, len(v.t.TaskList))
+	for _, e := range v.t.TaskList {
+		for i := range e {
+			list = append(list, e[i].info())
+		}
+	}
+	v.t.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func (v schedulerView) Get(taskName string) (TaskInfo, bool) {
+	return v.t.Info(taskName)
+}
+
+func (v schedulerView) Count() int {
+	v.t.mu.RLock()
+	defer v.t.mu.RUnlock()
+	return len(v.t.TaskList)
+}
+
+func (v schedulerView) Stats() map[string]TaskStats {
+	return v.t.Stats()
+}
+
+func (v schedulerView) NextRun(taskName string) (time.Time, bool) {
+	info, ok := v.t.Info(taskName)
+	return info.NextRun, ok
+}
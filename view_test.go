@@ -0,0 +1,57 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestView(t *testing.T) {
+	ts := newTestScheduler()
+	v := ts.View()
+	if _, ok := v.(interface{ RemoveTask(string) bool }); ok {
+		t.Fatal("view can remove a task")
+	}
+	if _, ok := v.(interface{ Pause(string) error }); ok {
+		t.Fatal("view can pause a task")
+	}
+	if v.Count() != 0 || len(v.List()) != 0 {
+		t.Fatalf("view of an empty scheduler has %d tasks", v.Count())
+	}
+
+	for _, name := range []string{"report", "backup"} {
+		if _, err := ts.addTask(newTestTask(name).Frequently().Minutes(5).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	makeDue(t, ts, "report")
+	ts.RunPending()
+
+	// The view follows the scheduler without being created again
+	list := v.List()
+	if v.Count() != 2 || len(list) != 2 || list[0].Name != "backup" || list[1].Name != "report" {
+		t.Fatalf("view lists %v, want backup and report", list)
+	}
+	info, _ := ts.Info("report")
+	got, ok := v.Get(info.ID)
+	if !ok || got.Name != "report" {
+		t.Errorf("view gets %+v by ID, want report", got)
+	}
+	if next, _ := v.NextRun("report"); !next.Equal(info.NextRun) {
+		t.Errorf("view next run %v, want %v", next, info.NextRun)
+	}
+	if stats := v.Stats(); stats["report"].Runs != 1 {
+		t.Errorf("view stats %+v, want 1 run of report", stats["report"])
+	}
+
+	// Changing the returned values doesn't change the scheduler
+	v.Stats()["report"] = TaskStats{Runs: 99}
+	got.NextRun = time.Time{}
+	if info, _ := ts.Info("report"); info.Stats.Runs != 1 || info.NextRun.IsZero() {
+		t.Errorf("scheduler is changed from the view: %+v", info)
+	}
+
+	ts.RemoveTask("backup")
+	if _, ok := v.NextRun("backup"); ok || v.Count() != 1 {
+		t.Errorf("view still has the removed task, count %d", v.Count())
+	}
+}
@@ -0,0 +1,75 @@
+package isked
+
+import (
+	"errors"
+
+	"github.com/fatih/color"
+)
+
+// WaitFor method holds the task until the channel is closed or receives a value, regardless of its
+// schedule. A run that is due while waiting, runs right away once the channel fires, then the task
+// follows its schedule as usual.
+func (s *Tasks) WaitFor(ch <-chan struct{}) *Tasks {
+	if ch == nil {
+		s.setErr("WaitFor", errors.New("missing channel to wait for"))
+		return s
+	}
+	s.waitFor = ch
+	return s
+}
+
+// prepareGate creates the signal of the removed task for its gate before the task is stored, a copy of
+// another task doesn't share its signal
+func (s *Tasks) prepareGate() {
+	s.gateDone = nil
+	if s.waitFor != nil {
+		s.gateDone = make(chan struct{})
+	}
+}
+
+// ungate stops the gate of the task that is removed from the task list, the lock of the task list must be held
+func (s *Tasks) ungate() {
+	if s.gateDone != nil {
+		close(s.gateDone)
+		s.gateDone = nil
+	}
+}
+
+// ungateAll stops the gates of all the tasks that are removed, the lock of the task list must be held
+func ungateAll(list map[string][]Tasks) {
+	for _, e := range list {
+		for i := range e {
+			e[i].ungate()
+		}
+	}
+}
+
+// gate waits for the channel of the stored task in the background then releases the task, it stops
+// waiting once the task is removed, e.g the task scheduler is closed. The lock of the task list must not be held.
+func (t *TaskScheduler) gate(s Tasks) {
+	if s.waitFor == nil {
+		return
+	}
+	go func(ch <-chan struct{}, done <-chan struct{}, id string) {
+		select {
+		case <-ch:
+		case <-done:
+			return
+		}
+		t.mu.Lock()
+		name := ""
+		if cur, ok := t.lookup(id); ok && len(cur) > 0 && cur[0].waitFor == ch {
+			cur[0].waitFor = nil
+			name = cur[0].Name
+		}
+		t.mu.Unlock()
+		if len(name) == 0 {
+			return // The task has been removed or replaced in the meantime
+		}
+		t.notify()
+
+		msg := name + " is released, its channel has fired"
+		logger().Infow(msg, s.logKV()...)
+		color.Cyan(msg)
+	}(s.waitFor, s.gateDone, s.id)
+}
@@ -0,0 +1,73 @@
+package isked
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWaitFor(t *testing.T) {
+	ts := newTestScheduler()
+	ready := make(chan struct{})
+	runs := 0
+	if _, err := ts.addTask(newTestTask("consumer").Frequently().Minutes(1).WaitFor(ready).ExecFunc(func() { runs++ })); err != nil {
+		t.Fatal(err)
+	}
+
+	// Due but held by the channel
+	for i := 0; i < 3; i++ {
+		makeDue(t, ts, "consumer")
+		ts.RunPending()
+	}
+	if runs != 0 {
+		t.Fatalf("%d runs before the channel fired, want 0", runs)
+	}
+
+	close(ready)
+	waitUntil(t, time.Second, func() bool {
+		ts.mu.RLock()
+		defer ts.mu.RUnlock()
+		return ts.TaskList["consumer"][0].waitFor == nil
+	})
+	ts.RunPending()
+	if runs != 1 {
+		t.Fatalf("%d runs after the channel fired, want the due run", runs)
+	}
+
+	// Then it follows its schedule
+	info, _ := ts.Info("consumer")
+	if d := time.Until(info.NextRun); d <= 0 || d > time.Minute {
+		t.Errorf("next run in %v, want within the 1 minute interval", d)
+	}
+	ts.RunPending()
+	if runs != 1 {
+		t.Errorf("%d runs before the next schedule, want 1", runs)
+	}
+
+	if _, err := ts.addTask(newTestTask("nil-channel").Frequently().Minutes(1).WaitFor(nil).ExecFunc(func() {})); !hasProblem(err, "WaitFor") {
+		t.Errorf("nil channel error %v, want a WaitFor problem", err)
+	}
+}
+
+func TestWaitForNoLeak(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		remove func(ts *TaskScheduler)
+	}{
+		{"remove", func(ts *TaskScheduler) { ts.RemoveTask("gated") }},
+		{"reset", func(ts *TaskScheduler) { ts.Reset() }},
+		{"close", func(ts *TaskScheduler) { ts.Close() }},
+	} {
+		before := runtime.NumGoroutine()
+		ts := newTestScheduler()
+		never := make(chan struct{})
+		if _, err := ts.addTask(newTestTask("gated").Frequently().Minutes(1).WaitFor(never).ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+		if n := runtime.NumGoroutine(); n != before+1 {
+			t.Fatalf("%s: %d goroutines, want the gate of the task", tt.name, n-before)
+		}
+		tt.remove(ts)
+		waitUntil(t, time.Second, func() bool { return runtime.NumGoroutine() == before })
+	}
+}
@@ -0,0 +1,102 @@
+package isked
+
+import (
+	"time"
+)
+
+// ChangeKind is the kind of change in the schedule of a task
+type ChangeKind string
+
+// List of the kinds of change
+const (
+	ChangeAdded       ChangeKind = "added"
+	ChangeRemoved     ChangeKind = "removed"
+	ChangePaused      ChangeKind = "paused"
+	ChangeResumed     ChangeKind = "resumed"
+	ChangeRescheduled ChangeKind = "rescheduled"
+)
+
+// _watchBuffer is the number of changes each watch channel holds before the oldest one is dropped
+const _watchBuffer = 64
+
+// ScheduleChange is the change in the schedule of a task that is sent to the watch channels
+type ScheduleChange struct {
+	Kind    ChangeKind
+	Name    string
+	ID      string
+	NextRun time.Time // zero if the task is removed
+	Time    time.Time // DateTime of the change
+}
+
+// Watch returns a channel that receives the changes in the schedule of the tasks, e.g to update a dashboard
+// without polling. The oldest change is dropped when the channel is full, it's closed by the 'Close' method.
+func (t *TaskScheduler) Watch() <-chan ScheduleChange {
+	ch := make(chan ScheduleChange, _watchBuffer)
+	t.watchMu.Lock()
+	defer t.watchMu.Unlock()
+	if t.isClosed() {
+		close(ch)
+		return ch
+	}
+	t.watchers = append(t.watchers, ch)
+	return ch
+}
+
+// SetOnDroppedEvent sets the fn that is called each time a change is dropped from a full watch channel,
+// with the number of changes dropped so far, so the gaps in the changes are not silent. It's called in
+// its own goroutine since the changes are sent while the task list is locked, use a nil fn to turn it off.
+func (t *TaskScheduler) SetOnDroppedEvent(fn func(count int)) {
+	t.watchMu.Lock()
+	defer t.watchMu.Unlock()
+	t.onDropped = fn
+}
+
+// DroppedEvents gets the number of changes dropped from the full watch channels so far
+func (t *TaskScheduler) DroppedEvents() int {
+	t.watchMu.Lock()
+	defer t.watchMu.Unlock()
+	return t.droppedChanges
+}
+
+// emit sends the change of the task to the watch channels without blocking
+func (t *TaskScheduler) emit(kind ChangeKind, s *Tasks) {
+	t.watchMu.Lock()
+	defer t.watchMu.Unlock()
+	if len(t.watchers) == 0 {
+		return
+	}
+	change := ScheduleChange{Kind: kind, Name: s.Name, ID: s.id, Time: time.Now()}
+	if kind != ChangeRemoved {
+		change.NextRun = s.nextRunTime
+	}
+	for _, ch := range t.watchers {
+		select {
+		case ch <- change:
+			continue
+		default:
+		}
+		// Drop the oldest change to make room, only the receiver can take from it in the meantime
+		select {
+		case <-ch:
+			t.droppedChanges++
+			if t.onDropped != nil {
+				go t.onDropped(t.droppedChanges)
+			}
+		default:
+		}
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+// closeWatchers closes all the watch channels
+func (t *TaskScheduler) closeWatchers() {
+	t.watchMu.Lock()
+	defer t.watchMu.Unlock()
+	for _, ch := range t.watchers {
+		close(ch)
+	}
+	t.watchers = nil
+}
@@ -0,0 +1,100 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	ts := newTestScheduler()
+	changes := ts.Watch()
+	if _, err := ts.addTask(newTestTask("watched").Frequently().Minutes(1).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Pause("watched"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Resume("watched", ResumeSkipMissed); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Trigger("watched"); err != nil {
+		t.Fatal(err)
+	}
+	ts.RemoveTask("watched")
+
+	for _, want := range []ChangeKind{ChangeAdded, ChangePaused, ChangeResumed, ChangeRescheduled, ChangeRemoved} {
+		select {
+		case c := <-changes:
+			if c.Kind != want || c.Name != "watched" {
+				t.Errorf("change %s of %s, want %s", c.Kind, c.Name, want)
+			}
+			if want == ChangeRescheduled && c.NextRun.After(time.Now()) {
+				t.Errorf("rescheduled change with the next run %v, want the triggered run", c.NextRun)
+			}
+			if want == ChangeRemoved && !c.NextRun.IsZero() {
+				t.Errorf("removed change with the next run %v", c.NextRun)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("missing the %s change", want)
+		}
+	}
+
+	select {
+	case c := <-changes:
+		t.Errorf("unexpected change %s of %s", c.Kind, c.Name)
+	default:
+	}
+
+	// A full channel drops its oldest change to keep the latest one
+	base := time.Now().Add(time.Hour)
+	s := newTestTask("burst")
+	for i := 0; i <= _watchBuffer; i++ {
+		s.nextRunTime = base.Add(time.Duration(i) * time.Minute)
+		ts.emit(ChangeRescheduled, s)
+	}
+	var kept []time.Time
+	for len(changes) > 0 {
+		kept = append(kept, (<-changes).NextRun)
+	}
+	if len(kept) != _watchBuffer || !kept[0].Equal(base.Add(time.Minute)) || !kept[len(kept)-1].Equal(s.nextRunTime) {
+		t.Errorf("kept %d changes from %v, want the latest %d", len(kept), kept[0], _watchBuffer)
+	}
+
+	ts.Close()
+	if _, ok := <-changes; ok {
+		t.Error("watch channel is not closed by Close")
+	}
+}
+
+func TestOnDroppedEvent(t *testing.T) {
+	ts := newTestScheduler()
+	counts := make(chan int, 10)
+	ts.SetOnDroppedEvent(func(count int) { counts <- count })
+	changes := ts.Watch()
+
+	s := newTestTask("noisy").Frequently().Minutes(1).ExecFunc(func() {})
+	for i := 0; i < _watchBuffer+3; i++ {
+		ts.emit(ChangeRescheduled, s)
+	}
+	if got := ts.DroppedEvents(); got != 3 {
+		t.Errorf("DroppedEvents = %d, want 3", got)
+	}
+	if got := len(changes); got != _watchBuffer {
+		t.Errorf("%d changes kept, want %d", got, _watchBuffer)
+	}
+
+	highest := 0
+	for i := 0; i < 3; i++ {
+		select {
+		case c := <-counts:
+			if c > highest {
+				highest = c
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("drop callback called %d times, want 3", i)
+		}
+	}
+	if highest != 3 {
+		t.Errorf("highest drop count %d, want 3", highest)
+	}
+}
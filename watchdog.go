@@ -0,0 +1,69 @@
+package isked
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// SetRunWatchdog sets how long a run can take before it's logged as stuck, a zero duration turns it off.
+// The stuck run is only reported, it can't be interrupted and the next runs are scheduled as usual.
+// Use 'SetReleaseStuckRuns' so the stuck run doesn't hold back the next runs of the 'MinGap' method.
+func (t *TaskScheduler) SetRunWatchdog(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	t.runWatchdog = d
+}
+
+// SetReleaseStuckRuns sets if the run reported as stuck by the watchdog is no longer counted as in progress,
+// so the task with the 'MinGap' method runs again once its gap has passed instead of waiting for the
+// stuck run to end. It's off by default and only applies while the watchdog is on.
+func (t *TaskScheduler) SetReleaseStuckRuns(release bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.releaseStuck = release
+}
+
+// releaseRun stops counting the stuck run of the task as in progress, it's recorded once it ends
+func (t *TaskScheduler) releaseRun(taskName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.releaseStuck {
+		return false
+	}
+	cur, ok := t.TaskList[taskName]
+	if !ok || len(cur) == 0 || cur[0].running == 0 {
+		return false
+	}
+	cur[0].running--
+	cur[0].released++
+	return true
+}
+
+// watchRun starts the watchdog of the run, the returned func stops it once the run is done
+func (t *TaskScheduler) watchRun(s *Tasks, start time.Time) func() {
+	t.mu.RLock()
+	d := t.runWatchdog
+	t.mu.RUnlock()
+	if d <= 0 {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(d, func() {
+		startedAt, _ := formatDT(start, logDateTimeFormat)
+		msg := fmt.Sprintf("%s is still running after %v, started at: %s", s.Name, d, startedAt)
+		logger().Errorw(msg, s.logKV()...)
+		color.Red(msg)
+
+		if s.minGap > 0 && t.releaseRun(s.Name) {
+			msg := s.Name + " is no longer counted as running, its next runs are scheduled after its minimum gap"
+			logger().Warnw(msg, s.logKV()...)
+			color.Yellow(msg)
+		}
+	})
+	return func() { timer.Stop() }
+}
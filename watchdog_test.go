@@ -0,0 +1,59 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+// runningCount returns the runs of the task that are counted as in progress
+func runningCount(ts *TaskScheduler, name string) int {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	return ts.TaskList[name][0].running
+}
+
+// waitUntil polls the condition until it's true or the timeout has passed
+func waitUntil(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReleaseStuckRuns(t *testing.T) {
+	for _, release := range []bool{false, true} {
+		ts := newTestScheduler()
+		ts.SetRunWatchdog(20 * time.Millisecond)
+		ts.SetReleaseStuckRuns(release)
+		unblock := make(chan struct{})
+		if _, err := ts.addTask(newTestTask("stuck").Frequently().Seconds(1).MinGap(time.Millisecond).ExecFunc(func() { <-unblock })); err != nil {
+			t.Fatal(err)
+		}
+		cur, _ := ts.Get("stuck")
+		done := make(chan struct{})
+		go func(s Tasks) {
+			ts.execute(s)
+			close(done)
+		}(cur[0])
+		waitUntil(t, time.Second, func() bool { return runningCount(ts, "stuck") == 1 })
+
+		time.Sleep(60 * time.Millisecond) // Reported as stuck by the watchdog
+		cur, _ = ts.Get("stuck")
+		if deferred := ts.deferMinGap(&cur[0], time.Now()); deferred == release {
+			t.Errorf("release %v: deferred %v while the stuck run is in progress", release, deferred)
+		}
+
+		close(unblock)
+		<-done
+		ts.mu.RLock()
+		s := ts.TaskList["stuck"][0]
+		ts.mu.RUnlock()
+		if s.running != 0 || s.released != 0 {
+			t.Errorf("release %v: %d running and %d released after the run ended", release, s.running, s.released)
+		}
+	}
+}
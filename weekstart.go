@@ -0,0 +1,89 @@
+package isked
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// _maxWeekSearch is how many weeks ahead the week of the month of a weekly task is looked for
+const _maxWeekSearch = 260
+
+// SetWeekStart sets the first day of the week used to number the weeks of the month for the 'InWeek' method,
+// e.g time.Monday for the weeks that start on Monday, it's Sunday by default. It applies to the tasks added
+// from now on and to the existing ones, their next run is computed again with the new numbering.
+func (t *TaskScheduler) SetWeekStart(d time.Weekday) {
+	if d < time.Sunday || d > time.Saturday {
+		d = time.Sunday
+	}
+	now := time.Now()
+	t.mu.Lock()
+	t.weekStart = d
+	var moved []Tasks
+	for _, e := range t.TaskList {
+		if len(e) == 0 {
+			continue
+		}
+		e[0].weekStart = d
+		if e[0].weekOfMonth == 0 || e[0].RunType != _weekly || e[0].nextRunTime.IsZero() {
+			continue
+		}
+		if next := e[0].nextSchedule(now); !next.IsZero() && !next.Equal(e[0].nextRunTime) {
+			e[0].nextRunTime = next
+			t.emit(ChangeRescheduled, &e[0])
+			moved = append(moved, e[0])
+		}
+	}
+	t.mu.Unlock()
+	t.notify()
+
+	for _, s := range moved {
+		nextSched, _ := formatDT(s.nextRunTime, logDateTimeFormat)
+		msg := s.Name + " follows the weeks starting on " + d.String() + ", next schedule to run on: " + nextSched
+		logger().Infow(msg, s.logKV()...)
+		color.Magenta(msg)
+	}
+}
+
+// weekStartDay returns the first day of the week of the task scheduler
+func (t *TaskScheduler) weekStartDay() time.Weekday {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.weekStart
+}
+
+// InWeek method runs the weekly task only in the week of the month from 1 to 6, e.g the first Monday
+// with '.Weekly().Monday().InWeek(1)'. The first week is the one with the 1st day of the month, the next
+// weeks start on the day set with 'SetWeekStart'. The months without the day in that week are skipped.
+func (s *Tasks) InWeek(n int) *Tasks {
+	if n < 1 || n > 6 {
+		s.setErr("InWeek", fmt.Errorf("invalid week of the month %d, use 1 to 6", n))
+		return s
+	}
+	s.weekOfMonth = n
+	return s
+}
+
+// weekOfMonth returns the week of the month of the DateTime from 1, the weeks start on the given day
+func weekOfMonth(dt time.Time, start time.Weekday) int {
+	first := time.Date(dt.Year(), dt.Month(), 1, 0, 0, 0, 0, dt.Location())
+	offset := (int(first.Weekday()) - int(start) + 7) % 7
+	return (dt.Day()-1+offset)/7 + 1
+}
+
+// inWeekRun moves the weekly run forward by weeks until it's in the week of the month of the task,
+// it returns the zero time if there's no such week
+func (s *Tasks) inWeekRun(next time.Time) time.Time {
+	for i := 0; i < _maxWeekSearch; i++ {
+		if weekOfMonth(next, s.weekStart) == s.weekOfMonth {
+			return next
+		}
+		next = next.AddDate(0, 0, 7)
+	}
+	msg := s.Name + " has no " + s.dayName.String() + " in week " + strconv.Itoa(s.weekOfMonth) + " of the month"
+	logger().Warnw(msg, s.logKV()...)
+	color.Yellow(msg)
+	return time.Time{}
+}
@@ -0,0 +1,136 @@
+package isked
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekOfMonth(t *testing.T) {
+	// March 2031 starts on a Saturday
+	tests := []struct {
+		day            int
+		sunday, monday int
+	}{
+		{1, 1, 1},  // Saturday
+		{2, 2, 1},  // Sunday
+		{3, 2, 2},  // Monday
+		{8, 2, 2},  // Saturday
+		{9, 3, 2},  // Sunday
+		{10, 3, 3}, // Monday
+		{30, 6, 5}, // Sunday
+		{31, 6, 6}, // Monday
+	}
+	for _, tt := range tests {
+		dt := time.Date(2031, time.March, tt.day, 12, 0, 0, 0, time.Local)
+		if got := weekOfMonth(dt, time.Sunday); got != tt.sunday {
+			t.Errorf("%s %d: week %d starting on Sunday, want %d", dt.Weekday(), tt.day, got, tt.sunday)
+		}
+		if got := weekOfMonth(dt, time.Monday); got != tt.monday {
+			t.Errorf("%s %d: week %d starting on Monday, want %d", dt.Weekday(), tt.day, got, tt.monday)
+		}
+	}
+}
+
+func TestInWeek(t *testing.T) {
+	now := time.Date(2031, time.February, 28, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		weekday time.Weekday
+		week    int
+		start   time.Weekday
+		want    time.Time
+	}{
+		// The 1st of March is a Saturday, it's in the first week either way
+		{time.Saturday, 1, time.Sunday, time.Date(2031, time.March, 1, 9, 0, 0, 0, time.Local)},
+		{time.Saturday, 1, time.Monday, time.Date(2031, time.March, 1, 9, 0, 0, 0, time.Local)},
+		// The Sunday after it starts the second week only if the weeks start on Sunday
+		{time.Sunday, 1, time.Sunday, time.Date(2031, time.June, 1, 9, 0, 0, 0, time.Local)},
+		{time.Sunday, 1, time.Monday, time.Date(2031, time.March, 2, 9, 0, 0, 0, time.Local)},
+		{time.Monday, 2, time.Sunday, time.Date(2031, time.March, 3, 9, 0, 0, 0, time.Local)},
+		{time.Monday, 2, time.Monday, time.Date(2031, time.March, 3, 9, 0, 0, 0, time.Local)},
+		{time.Sunday, 6, time.Sunday, time.Date(2031, time.March, 30, 9, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		s := newTestTask("in-week").Weekly().At("09:00").InWeek(tt.week)
+		s.dayName, s.weekStart = tt.weekday, tt.start
+		if got := s.nextSchedule(now); !got.Equal(tt.want) {
+			t.Errorf("%s of week %d starting on %s: next run %v, want %v", tt.weekday, tt.week, tt.start, got, tt.want)
+		}
+	}
+
+	// A Sunday is never in the sixth week of the weeks starting on Monday, no run is found
+	s := newTestTask("never").Weekly().Sunday().At("09:00").InWeek(6)
+	s.weekStart = time.Monday
+	if got := s.nextSchedule(now); !got.IsZero() {
+		t.Errorf("next run %v, want none", got)
+	}
+}
+
+func TestSetWeekStart(t *testing.T) {
+	ts := newTestScheduler()
+	if _, err := ts.addTask(newTestTask("first-sunday").Weekly().Sunday().At("09:00").InWeek(1).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.addTask(newTestTask("every-sunday").Weekly().Sunday().At("09:00").ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	every, _ := ts.Info("every-sunday")
+
+	for _, start := range []time.Weekday{time.Monday, time.Sunday} {
+		ts.SetWeekStart(start)
+		info, _ := ts.Info("first-sunday")
+		next := info.NextRun
+		if next.Weekday() != time.Sunday || weekOfMonth(next, start) != 1 || info.WeekOfMonth != 1 {
+			t.Errorf("weeks starting on %s: next run %v, want a Sunday in the first week", start, next)
+		}
+		if ok, _ := ts.WouldRunAt("first-sunday", next); !ok {
+			t.Errorf("weeks starting on %s: doesn't run at its next run %v", start, next)
+		}
+		if ok, _ := ts.WouldRunAt("first-sunday", next.AddDate(0, 0, 7)); ok {
+			t.Errorf("weeks starting on %s: runs in the second week", start)
+		}
+	}
+	if cfg := ts.Config(); cfg.WeekStart != time.Sunday {
+		t.Errorf("config week start %s, want Sunday", cfg.WeekStart)
+	}
+	// Only the tasks with a week of the month are affected
+	if info, _ := ts.Info("every-sunday"); !info.NextRun.Equal(every.NextRun) {
+		t.Errorf("weekly task moved to %v, want %v", info.NextRun, every.NextRun)
+	}
+	if desc, _ := ts.Describe("first-sunday"); desc != "Weekly on Sunday of week 1 at 09:00" {
+		t.Errorf("described as %q", desc)
+	}
+
+	for _, s := range []*Tasks{
+		newTestTask("week-zero").Weekly().Monday().At("09:00").InWeek(0),
+		newTestTask("week-seven").Weekly().Monday().At("09:00").InWeek(7),
+		newTestTask("daily").Daily().At("09:00").InWeek(1),
+	} {
+		if _, err := ts.addTask(s.ExecFunc(func() {})); !hasProblem(err, "InWeek") {
+			t.Errorf("%s: error %v, want an InWeek problem", s.Name, err)
+		}
+	}
+}
+
+func TestWeekOfMonthRoundTrip(t *testing.T) {
+	src := newTestScheduler()
+	if _, err := src.addTask(newTestTask("third-friday").Weekly().Friday().At("17:00").InWeek(3).ExecFunc(func() {})); err != nil {
+		t.Fatal(err)
+	}
+	def, err := src.Export("third-friday")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.WeekOfMonth != 3 {
+		t.Fatalf("exported week %d, want 3", def.WeekOfMonth)
+	}
+	def.NextRun = time.Time{}
+	dst := newTestScheduler()
+	dst.SetWeekStart(time.Monday)
+	if err := dst.Import(def, func() {}); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := dst.Info("third-friday")
+	if info.NextRun.Weekday() != time.Friday || weekOfMonth(info.NextRun, time.Monday) != 3 {
+		t.Errorf("imported next run %v, want a Friday in the third week starting on Monday", info.NextRun)
+	}
+}
@@ -0,0 +1,77 @@
+package isked
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// WouldRunAt checks if the rule of the task fires at the given time within a second, e.g to check a
+// schedule without running it. The blackout window is taken into account, tasks that use the
+// 'NextFunc' method can't be checked.
+func (t *TaskScheduler) WouldRunAt(taskName string, at time.Time) (bool, error) {
+	t.mu.RLock()
+	taskData, ok := t.lookup(taskName)
+	var s Tasks
+	if ok && len(taskData) > 0 {
+		s = taskData[0]
+	}
+	t.mu.RUnlock()
+	if !ok || len(taskData) == 0 {
+		return false, fmt.Errorf("%s: %w", taskName, ErrTaskNotFound)
+	}
+	if s.nextFunc != nil {
+		return false, fmt.Errorf("%s: tasks with a next func can't be checked", taskName)
+	}
+	if _, inBlackout := s.blackoutEndsAt(at); inBlackout {
+		return false, nil
+	}
+	return s.firesAt(at), nil
+}
+
+// firesAt checks if the rule of the task fires at the given time within a second
+func (s *Tasks) firesAt(at time.Time) bool {
+	within := func(slot time.Time) bool {
+		return !slot.IsZero() && !at.Before(slot) && at.Sub(slot) < time.Second
+	}
+
+	switch s.RunType {
+	case _onetime:
+		if within(s.nextRunTime) {
+			return true
+		}
+		for _, d := range s.dates {
+			if within(d) {
+				return true
+			}
+		}
+		return false
+
+	case _frequently:
+		interval := s.interval()
+		if s.nextRunTime.IsZero() || interval <= 0 {
+			return false
+		}
+		offset := at.Sub(s.nextRunTime) % interval
+		if offset < 0 {
+			offset += interval
+		}
+		return offset < time.Second
+	}
+
+	runHour, _ := strconv.Atoi(s.runAtHour)
+	runMinute, _ := strconv.Atoi(s.runAtMinute)
+	lt := at.In(s.location())
+	if lt.Hour() != runHour || lt.Minute() != runMinute || lt.Second() != 0 {
+		return false
+	}
+	switch s.RunType {
+	case _daily:
+		return true
+	case _weekly:
+		return lt.Weekday() == s.dayName && (s.weekOfMonth == 0 || weekOfMonth(lt, s.weekStart) == s.weekOfMonth)
+	case _monthly:
+		return lt.Day() == s.runDay()
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package isked
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWouldRunAt(t *testing.T) {
+	ts := newTestScheduler()
+	for _, s := range []*Tasks{
+		newTestTask("daily").Daily().At("09:30"),
+		newTestTask("weekly").Weekly().Monday().At("09:30"),
+		newTestTask("monthly").Monthly().Every(15).At("09:30"),
+	} {
+		if _, err := ts.addTask(s.ExecFunc(func() {})); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Monday the 15th
+	at := time.Date(2031, time.September, 15, 9, 30, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		at   time.Time
+		want map[string]bool
+	}{
+		{"matching", at, map[string]bool{"daily": true, "weekly": true, "monthly": true}},
+		{"within-a-second", at.Add(900 * time.Millisecond), map[string]bool{"daily": true, "weekly": true, "monthly": true}},
+		{"a-second-later", at.Add(time.Second), map[string]bool{"daily": false, "weekly": false, "monthly": false}},
+		{"other-minute", at.Add(time.Minute), map[string]bool{"daily": false, "weekly": false, "monthly": false}},
+		{"tuesday-16th", at.AddDate(0, 0, 1), map[string]bool{"daily": true, "weekly": false, "monthly": false}},
+		{"next-monday", at.AddDate(0, 0, 7), map[string]bool{"daily": true, "weekly": true, "monthly": false}},
+		{"next-15th", at.AddDate(0, 1, 0), map[string]bool{"daily": true, "weekly": false, "monthly": true}},
+	}
+	for _, tt := range tests {
+		for name, want := range tt.want {
+			got, err := ts.WouldRunAt(name, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("%s at %v: WouldRunAt = %v, want %v", name, tt.at, got, want)
+			}
+		}
+	}
+
+	if _, err := ts.WouldRunAt("missing", at); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("missing task error %v, want ErrTaskNotFound", err)
+	}
+}